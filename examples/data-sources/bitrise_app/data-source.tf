@@ -0,0 +1,18 @@
+# Look up an app by its slug
+data "bitrise_app" "by_slug" {
+  app_slug = "your-app-slug"
+}
+
+# Look up an app by the repository it builds
+data "bitrise_app" "by_repo" {
+  organization_slug = "my-organization"
+  repo_url          = "git@github.com:myorg/myrepo.git"
+}
+
+output "app_title" {
+  value = data.bitrise_app.by_repo.title
+}
+
+output "app_stack" {
+  value = data.bitrise_app.by_slug.stack
+}
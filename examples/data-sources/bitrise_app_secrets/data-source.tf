@@ -0,0 +1,22 @@
+# Audit the secrets of an app
+data "bitrise_app_secrets" "audit" {
+  app_slug = "your-app-slug"
+}
+
+# Fail the plan when a secret is exposed to pull requests
+check "no_secrets_exposed_to_pull_requests" {
+  assert {
+    condition     = length([for s in data.bitrise_app_secrets.audit.secrets : s.name if s.is_exposed_for_pull_requests]) == 0
+    error_message = "Secrets exposed to pull requests: ${join(", ", [for s in data.bitrise_app_secrets.audit.secrets : s.name if s.is_exposed_for_pull_requests])}"
+  }
+}
+
+# Only the signing related secrets
+data "bitrise_app_secrets" "signing" {
+  app_slug   = "your-app-slug"
+  name_regex = "^SIGNING_"
+}
+
+output "unprotected_signing_secrets" {
+  value = [for s in data.bitrise_app_secrets.signing.secrets : s.name if !s.is_protected]
+}
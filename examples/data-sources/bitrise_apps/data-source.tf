@@ -0,0 +1,21 @@
+# Every iOS app of the organization hosted on GitHub
+data "bitrise_apps" "ios" {
+  organization_slug = "my-organization"
+  project_type      = "ios"
+  repo              = "github"
+  title_regex       = "^mobile-"
+  sort_by           = "created_at"
+}
+
+# Fan a shared secret out to all of them
+resource "bitrise_app_secret" "sentry_dsn" {
+  for_each = { for app in data.bitrise_apps.ios.apps : app.slug => app }
+
+  app_slug = each.key
+  name     = "SENTRY_DSN"
+  value    = var.sentry_dsn
+}
+
+output "ios_app_titles" {
+  value = [for app in data.bitrise_apps.ios.apps : app.title]
+}
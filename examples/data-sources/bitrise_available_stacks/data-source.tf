@@ -41,3 +41,15 @@ output "xcode_stacks" {
   description = "All Xcode stacks"
   value       = local.xcode_stacks
 }
+
+# Example: Pick the latest supported Xcode stack without hardcoding it
+data "bitrise_available_stacks" "xcode" {
+  os                 = "osx"
+  name_regex         = "^osx-xcode-\\d+"
+  exclude_deprecated = true
+}
+
+output "latest_xcode_stack" {
+  description = "The most recent non-deprecated Xcode stack"
+  value       = element(data.bitrise_available_stacks.xcode.stacks, length(data.bitrise_available_stacks.xcode.stacks) - 1).id
+}
@@ -0,0 +1,9 @@
+# Check the settings of a workspace secret
+data "bitrise_workspace_secret" "artifactory_token" {
+  workspace_slug = "your-workspace-slug"
+  name           = "ARTIFACTORY_TOKEN"
+}
+
+output "artifactory_token_protected" {
+  value = data.bitrise_workspace_secret.artifactory_token.is_protected
+}
@@ -22,6 +22,20 @@ resource "bitrise_app" "my_app" {
   is_public         = false
 }
 
+# Protect a long-lived app and only disable it on destroy
+resource "bitrise_app" "production_app" {
+  repo                = "github"
+  repo_url            = "https://github.com/myorg/production"
+  type                = "git"
+  git_repo_slug       = "production"
+  git_owner           = "myorg"
+  organization_slug   = "my-bitrise-org"
+  title               = "Production"
+  default_branch      = "main"
+  deletion_protection = true
+  on_destroy          = "disable"
+}
+
 # Register a public open-source application
 resource "bitrise_app" "public_app" {
   repo              = "github"
@@ -0,0 +1,30 @@
+# Register, add the SSH key, finish and hook up an app in one resource
+resource "bitrise_app_onboarding" "my_app" {
+  repo              = "github"
+  repo_url          = "git@github.com:myorg/myrepo.git"
+  git_owner         = "myorg"
+  git_repo_slug     = "myrepo"
+  organization_slug = "my-bitrise-org"
+
+  ssh_key = {
+    auth_ssh_private_key = file("~/.ssh/bitrise_myrepo")
+    auth_ssh_public_key  = file("~/.ssh/bitrise_myrepo.pub")
+  }
+
+  finish = {
+    project_type = "ios"
+    stack_id     = "osx-xcode-15.0.x"
+    config       = "default-ios-config"
+    mode         = "manual"
+
+    envs = {
+      BITRISE_PROJECT_PATH = "MyApp.xcodeproj"
+    }
+  }
+
+  register_webhook = true
+}
+
+output "onboarded_app_slug" {
+  value = bitrise_app_onboarding.my_app.app_slug
+}
@@ -0,0 +1,25 @@
+# Manage every secret of an app in one resource
+resource "bitrise_app_secrets" "my_app" {
+  app_slug = bitrise_app.my_app.app_slug
+
+  secrets = {
+    API_KEY = {
+      value = var.api_key
+    }
+    SIGNING_PASSWORD = {
+      value        = var.signing_password
+      is_protected = true
+    }
+    PR_TOKEN = {
+      value                        = var.pr_token
+      is_exposed_for_pull_requests = true
+    }
+  }
+
+  # Delete secrets added by hand in the Bitrise UI
+  authoritative = true
+}
+
+output "unmanaged_secrets" {
+  value = bitrise_app_secrets.my_app.unmanaged_secrets
+}
@@ -74,6 +74,22 @@ resource "bitrise_app_ssh" "app_ssh_config" {
   auth_ssh_public_key  = tls_private_key.app_ssh.public_key_openssh
 }
 
+# Example 5: Let the provider generate the keypair, rotated by bumping the trigger
+resource "bitrise_app_ssh" "managed" {
+  app_slug = bitrise_app.my_app.app_slug
+
+  generate_key = {
+    algorithm = "ed25519"
+  }
+
+  rotation_trigger = "2024-01"
+}
+
+output "managed_ssh_public_key" {
+  value       = bitrise_app_ssh.managed.auth_ssh_public_key
+  description = "The public key generated by bitrise_app_ssh, to add as a deploy key"
+}
+
 # Output the public key (safe to output, unlike private key)
 output "ssh_public_key" {
   value       = tls_private_key.bitrise_ssh.public_key_openssh
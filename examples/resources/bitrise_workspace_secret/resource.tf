@@ -0,0 +1,54 @@
+terraform {
+  required_providers {
+    bitrise = {
+      source = "registry.terraform.io/your-org/bitrise"
+    }
+  }
+}
+
+provider "bitrise" {
+  endpoint = "https://api.bitrise.io"
+  token    = var.bitrise_token
+}
+
+variable "bitrise_token" {
+  description = "Bitrise Personal Access Token"
+  type        = string
+  sensitive   = true
+}
+
+variable "workspace_slug" {
+  description = "Bitrise Workspace Slug"
+  type        = string
+}
+
+variable "artifactory_token" {
+  description = "Token shared by every app of the workspace"
+  type        = string
+  sensitive   = true
+}
+
+# Example 1: Basic workspace secret
+resource "bitrise_workspace_secret" "slack_webhook" {
+  workspace_slug = var.workspace_slug
+  name           = "SLACK_WEBHOOK_URL"
+  value          = "https://hooks.slack.com/services/your/webhook"
+}
+
+# Example 2: Protected workspace secret
+resource "bitrise_workspace_secret" "artifactory_token" {
+  workspace_slug = var.workspace_slug
+  name           = "ARTIFACTORY_TOKEN"
+  value          = var.artifactory_token
+  is_protected   = true
+}
+
+# Example 3: Full configuration
+resource "bitrise_workspace_secret" "danger_token" {
+  workspace_slug               = var.workspace_slug
+  name                         = "DANGER_GITHUB_API_TOKEN"
+  value                        = "read-only-token"
+  is_protected                 = false
+  is_exposed_for_pull_requests = true
+  expand_in_step_inputs        = false
+}
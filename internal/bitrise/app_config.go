@@ -0,0 +1,62 @@
+package bitrise
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AppConfig holds the settings of an app that are only stored in its
+// bitrise.yml.
+type AppConfig struct {
+	// Stack is the default stack of the app, empty when the bitrise.yml does
+	// not set one.
+	Stack string
+	// MachineTypeID is the default machine type of the app.
+	MachineTypeID string
+	// Envs are the app level environment variables.
+	Envs map[string]string
+}
+
+type appConfigDocument struct {
+	Meta struct {
+		BitriseIO struct {
+			Stack         string `yaml:"stack"`
+			MachineTypeID string `yaml:"machine_type_id"`
+		} `yaml:"bitrise.io"`
+	} `yaml:"meta"`
+	App struct {
+		Envs []map[string]interface{} `yaml:"envs"`
+	} `yaml:"app"`
+}
+
+// ParseAppConfig extracts the stack, machine type and app level envs from a
+// bitrise.yml document.
+func ParseAppConfig(yml string) (*AppConfig, error) {
+	var doc appConfigDocument
+	if err := yaml.Unmarshal([]byte(yml), &doc); err != nil {
+		return nil, fmt.Errorf("unable to parse bitrise.yml: %w", err)
+	}
+
+	config := &AppConfig{
+		Stack:         doc.Meta.BitriseIO.Stack,
+		MachineTypeID: doc.Meta.BitriseIO.MachineTypeID,
+		Envs:          map[string]string{},
+	}
+
+	// Each env is a single key mapping, optionally next to its opts
+	for _, env := range doc.App.Envs {
+		for key, value := range env {
+			if key == "opts" {
+				continue
+			}
+			if value == nil {
+				config.Envs[key] = ""
+				continue
+			}
+			config.Envs[key] = fmt.Sprint(value)
+		}
+	}
+
+	return config, nil
+}
@@ -0,0 +1,217 @@
+package bitrise
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// App is an application as returned by GET /v0.1/apps/{app-slug}.
+type App struct {
+	Slug          string   `json:"slug"`
+	Title         string   `json:"title"`
+	ProjectType   string   `json:"project_type"`
+	Provider      string   `json:"provider"`
+	RepoOwner     string   `json:"repo_owner"`
+	RepoURL       string   `json:"repo_url"`
+	RepoSlug      string   `json:"repo_slug"`
+	DefaultBranch string   `json:"default_branch"`
+	IsDisabled    bool     `json:"is_disabled"`
+	Status        int      `json:"status"`
+	IsPublic      bool     `json:"is_public"`
+	Owner         AppOwner `json:"owner"`
+	AvatarURL     *string  `json:"avatar_url"`
+}
+
+// AppStatusActive is the App.Status of an app whose setup is finished.
+const AppStatusActive = 1
+
+// IsActive reports whether the setup of the app is finished.
+func (a *App) IsActive() bool {
+	return a.Status == AppStatusActive
+}
+
+// AppOwner is the account owning an app.
+type AppOwner struct {
+	AccountType string `json:"account_type"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+}
+
+// RegisterAppParams is the payload of POST /v0.1/apps/register.
+type RegisterAppParams struct {
+	Provider         string `json:"provider"`
+	IsPublic         bool   `json:"is_public"`
+	OrganizationSlug string `json:"organization_slug"`
+	RepoURL          string `json:"repo_url"`
+	Type             string `json:"type"`
+	GitRepoSlug      string `json:"git_repo_slug"`
+	GitOwner         string `json:"git_owner"`
+}
+
+// RegisterAppResponse is the response of POST /v0.1/apps/register.
+type RegisterAppResponse struct {
+	Status     string `json:"status"`
+	Slug       string `json:"slug"`
+	ProviderID string `json:"provider_id"`
+}
+
+// PatchAppParams is the payload of PATCH /v0.1/apps/{app-slug}. Nil and
+// empty fields are left untouched.
+type PatchAppParams struct {
+	IsPublic      *bool  `json:"is_public,omitempty"`
+	IsDisabled    *bool  `json:"is_disabled,omitempty"`
+	RepositoryURL string `json:"repository_url,omitempty"`
+	Title         string `json:"title,omitempty"`
+	DefaultBranch string `json:"default_branch,omitempty"`
+}
+
+// FinishAppParams is the payload of POST /v0.1/apps/{app-slug}/finish.
+type FinishAppParams struct {
+	ProjectType      string            `json:"project_type"`
+	StackID          string            `json:"stack_id"`
+	Config           string            `json:"config"`
+	Mode             string            `json:"mode"`
+	Envs             map[string]string `json:"envs"`
+	OrganizationSlug string            `json:"organization_slug"`
+}
+
+// RegisterSSHKeyParams is the payload of POST /v0.1/apps/{app-slug}/register-ssh-key.
+type RegisterSSHKeyParams struct {
+	AuthSSHPrivateKey                string `json:"auth_ssh_private_key"`
+	AuthSSHPublicKey                 string `json:"auth_ssh_public_key"`
+	IsRegisterKeyIntoProviderService bool   `json:"is_register_key_into_provider_service"`
+}
+
+func appPath(appSlug string) string {
+	return "/v0.1/apps/" + url.PathEscape(appSlug)
+}
+
+// RegisterApp registers a new app and returns its slug.
+func (c *Client) RegisterApp(ctx context.Context, params RegisterAppParams) (*RegisterAppResponse, error) {
+	var out RegisterAppResponse
+	if err := c.do(ctx, http.MethodPost, "/v0.1/apps/register", params, &out); err != nil {
+		return nil, err
+	}
+
+	if out.Slug == "" {
+		return nil, fmt.Errorf("POST /v0.1/apps/register: response did not contain an app slug")
+	}
+
+	return &out, nil
+}
+
+// GetApp returns the details of an app.
+func (c *Client) GetApp(ctx context.Context, appSlug string) (*App, error) {
+	var out struct {
+		Data App `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodGet, appPath(appSlug), nil, &out); err != nil {
+		return nil, err
+	}
+
+	return &out.Data, nil
+}
+
+// appPollInterval is the delay between two checks of WaitForApp.
+var appPollInterval = 5 * time.Second
+
+// WaitForApp polls an app until ready reports true for it or ctx is done. A
+// nil ready only waits for the app to be readable. Not found responses are
+// retried, as a freshly registered app may not be visible right away.
+func (c *Client) WaitForApp(ctx context.Context, appSlug string, ready func(*App) bool) (*App, error) {
+	for {
+		app, err := c.GetApp(ctx, appSlug)
+		if err == nil && (ready == nil || ready(app)) {
+			return app, nil
+		}
+		if err != nil && !IsNotFound(err) {
+			return nil, err
+		}
+
+		state := "not found"
+		if app != nil {
+			state = fmt.Sprintf("status %d", app.Status)
+		}
+		tflog.Debug(ctx, "Waiting for app to become ready", map[string]interface{}{"app_slug": appSlug, "state": state})
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("app %s did not become ready in time, last seen %s: %w", appSlug, state, ctx.Err())
+		case <-time.After(appPollInterval):
+		}
+	}
+}
+
+// PatchApp updates the mutable settings of an app. The payload carries
+// absolute values, so the request is safe to retry.
+func (c *Client) PatchApp(ctx context.Context, appSlug string, params PatchAppParams) error {
+	return c.do(WithRetryable(ctx), http.MethodPatch, appPath(appSlug), params, nil)
+}
+
+// DeleteApp deletes an app.
+func (c *Client) DeleteApp(ctx context.Context, appSlug string) error {
+	return c.do(ctx, http.MethodDelete, appPath(appSlug), nil, nil)
+}
+
+// FinishApp completes the setup of a registered app.
+func (c *Client) FinishApp(ctx context.Context, appSlug string, params FinishAppParams) error {
+	return c.do(ctx, http.MethodPost, appPath(appSlug)+"/finish", params, nil)
+}
+
+// RegisterSSHKey registers the SSH keypair used to clone the app's repository.
+// Registering the same keypair twice is harmless, so the request is retryable.
+func (c *Client) RegisterSSHKey(ctx context.Context, appSlug string, params RegisterSSHKeyParams) error {
+	return c.do(WithRetryable(ctx), http.MethodPost, appPath(appSlug)+"/register-ssh-key", params, nil)
+}
+
+// RegisterWebhook registers the Bitrise webhook on the app's repository so
+// that pushes and pull requests trigger builds.
+func (c *Client) RegisterWebhook(ctx context.Context, appSlug string) error {
+	return c.do(ctx, http.MethodPost, appPath(appSlug)+"/register-webhook", nil, nil)
+}
+
+// ListAppsParams are the server side filters of the app list endpoints.
+// Empty fields are not sent.
+type ListAppsParams struct {
+	// SortBy is either "last_build_at" or "created_at".
+	SortBy      string
+	ProjectType string
+}
+
+func (p ListAppsParams) query() url.Values {
+	query := url.Values{}
+	if p.SortBy != "" {
+		query.Set("sort_by", p.SortBy)
+	}
+	if p.ProjectType != "" {
+		query.Set("project_type", p.ProjectType)
+	}
+	return query
+}
+
+// ListApps returns every app the token has access to.
+func (c *Client) ListApps(ctx context.Context, params ListAppsParams) ([]App, error) {
+	return c.listApps(ctx, "/v0.1/apps", params)
+}
+
+// ListOrganizationApps returns every app owned by an organization.
+func (c *Client) ListOrganizationApps(ctx context.Context, orgSlug string, params ListAppsParams) ([]App, error) {
+	return c.listApps(ctx, organizationPath(orgSlug)+"/apps", params)
+}
+
+func (c *Client) listApps(ctx context.Context, path string, params ListAppsParams) ([]App, error) {
+	var apps []App
+	err := paginate(ctx, c, path, params.query(), func(page []App) {
+		apps = append(apps, page...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return apps, nil
+}
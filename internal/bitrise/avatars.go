@@ -0,0 +1,79 @@
+package bitrise
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type avatarCandidateParams struct {
+	Filename string `json:"filename"`
+	Filesize int    `json:"filesize"`
+}
+
+type avatarCandidate struct {
+	Slug      string `json:"slug"`
+	Filename  string `json:"filename"`
+	UploadURL string `json:"upload_url"`
+}
+
+type avatarPromoteParams struct {
+	IsPromoted bool `json:"is_promoted"`
+}
+
+func avatarCandidatesPath(appSlug string) string {
+	return appPath(appSlug) + "/avatar-candidates"
+}
+
+// UploadAppAvatar sets the avatar of an app: it creates an avatar candidate,
+// uploads content to the returned pre-signed URL and promotes the candidate.
+func (c *Client) UploadAppAvatar(ctx context.Context, appSlug, filename string, content []byte) error {
+	var out struct {
+		Data []avatarCandidate `json:"data"`
+	}
+	params := []avatarCandidateParams{{Filename: filename, Filesize: len(content)}}
+	if err := c.do(ctx, http.MethodPost, avatarCandidatesPath(appSlug), params, &out); err != nil {
+		return err
+	}
+	if len(out.Data) == 0 || out.Data[0].Slug == "" || out.Data[0].UploadURL == "" {
+		return fmt.Errorf("POST %s: response did not contain an avatar candidate", avatarCandidatesPath(appSlug))
+	}
+	candidate := out.Data[0]
+
+	if err := c.upload(ctx, candidate.UploadURL, content); err != nil {
+		return err
+	}
+
+	return c.do(ctx, http.MethodPatch, avatarCandidatesPath(appSlug)+"/"+url.PathEscape(candidate.Slug), avatarPromoteParams{IsPromoted: true}, nil)
+}
+
+// upload PUTs content to a pre-signed storage URL. The URL carries its own
+// credentials, so the request bypasses the authenticated API transport.
+func (c *Client) upload(ctx context.Context, uploadURL string, content []byte) error {
+	ctx = c.LogContext(ctx)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPut, uploadURL, bytes.NewReader(content))
+	if err != nil {
+		return fmt.Errorf("unable to create upload request: %w", err)
+	}
+
+	tflog.Debug(ctx, "Uploading file to pre-signed URL", map[string]interface{}{"size": len(content)})
+
+	httpResp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("unable to upload file: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode < 200 || httpResp.StatusCode > 299 {
+		body, _ := io.ReadAll(httpResp.Body)
+		return fmt.Errorf("unable to upload file: %s: %s", httpResp.Status, body)
+	}
+
+	return nil
+}
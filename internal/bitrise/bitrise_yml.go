@@ -0,0 +1,36 @@
+package bitrise
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+type bitriseYMLPayload struct {
+	AppConfigDatastoreYaml string `json:"app_config_datastore_yaml"`
+}
+
+// GetBitriseYML returns the bitrise.yml stored for an app. The endpoint
+// answers either with plain YAML or with a JSON envelope; both are handled.
+func (c *Client) GetBitriseYML(ctx context.Context, appSlug string) (string, error) {
+	body, header, err := c.doRaw(ctx, http.MethodGet, appPath(appSlug)+"/bitrise.yml", nil)
+	if err != nil {
+		return "", err
+	}
+
+	if strings.Contains(header.Get("Content-Type"), "application/json") || len(body) > 0 && body[0] == '{' {
+		var payload bitriseYMLPayload
+		if err := json.Unmarshal(body, &payload); err == nil {
+			return payload.AppConfigDatastoreYaml, nil
+		}
+	}
+
+	return string(body), nil
+}
+
+// UpdateBitriseYML replaces the bitrise.yml of an app. Although it is a POST,
+// the upload replaces the whole document and is therefore retryable.
+func (c *Client) UpdateBitriseYML(ctx context.Context, appSlug, content string) error {
+	return c.do(WithRetryable(ctx), http.MethodPost, appPath(appSlug)+"/bitrise.yml", bitriseYMLPayload{AppConfigDatastoreYaml: content}, nil)
+}
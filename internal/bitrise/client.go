@@ -0,0 +1,135 @@
+// Package bitrise is a small typed client for the Bitrise API v0.1 used by
+// the resources and data sources of the provider.
+package bitrise
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Client talks to the Bitrise API. Authentication is handled by the
+// transport of the wrapped *http.Client.
+type Client struct {
+	endpoint   string
+	httpClient *http.Client
+
+	sensitiveFields  []string
+	sensitiveRegexps []*regexp.Regexp
+
+	stacksMu sync.Mutex
+	stacks   map[string]Stack
+}
+
+// NewClient returns a Client sending requests to endpoint through httpClient.
+// The values of DefaultSensitiveFields and of any additional sensitiveFields
+// are masked in debug logs.
+func NewClient(endpoint string, httpClient *http.Client, sensitiveFields ...string) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	fields := append(append([]string{}, DefaultSensitiveFields...), sensitiveFields...)
+
+	return &Client{
+		endpoint:        strings.TrimRight(endpoint, "/"),
+		httpClient:      httpClient,
+		sensitiveFields: fields,
+		sensitiveRegexps: []*regexp.Regexp{
+			sensitiveFieldRegexp(fields),
+			authorizationHeaderRegexp,
+			privateKeyRegexp,
+		},
+	}
+}
+
+// Endpoint returns the base URL of the Bitrise API the client talks to.
+func (c *Client) Endpoint() string {
+	return c.endpoint
+}
+
+// do sends a JSON request and decodes the JSON response into out. Any non-2xx
+// response is returned as an *APIError.
+func (c *Client) do(ctx context.Context, method, path string, in, out interface{}) error {
+	body, _, err := c.doRaw(ctx, method, path, in)
+	if err != nil {
+		return err
+	}
+
+	if out == nil || len(body) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("%s %s: unable to parse response: %w", method, path, err)
+	}
+
+	return nil
+}
+
+// doRaw sends a request and returns the raw response body and headers.
+func (c *Client) doRaw(ctx context.Context, method, path string, in interface{}) ([]byte, http.Header, error) {
+	ctx = c.LogContext(ctx)
+
+	var reqBody io.Reader
+	var payload []byte
+	if in != nil {
+		var err error
+		if payload, err = json.Marshal(in); err != nil {
+			return nil, nil, fmt.Errorf("%s %s: unable to marshal payload: %w", method, path, err)
+		}
+		reqBody = bytes.NewReader(payload)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, c.endpoint+path, reqBody)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s %s: unable to create request: %w", method, path, err)
+	}
+	if in != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	tflog.Debug(ctx, "Sending Bitrise API request", map[string]interface{}{
+		"method":  method,
+		"path":    path,
+		"payload": string(payload),
+	})
+
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s %s: unable to read response: %w", method, path, err)
+	}
+
+	tflog.Debug(ctx, "Received Bitrise API response", map[string]interface{}{
+		"method": method,
+		"path":   path,
+		"status": httpResp.StatusCode,
+		"body":   string(respBody),
+	})
+
+	if httpResp.StatusCode < 200 || httpResp.StatusCode > 299 {
+		return nil, nil, &APIError{
+			Method:     method,
+			Path:       path,
+			StatusCode: httpResp.StatusCode,
+			Status:     httpResp.Status,
+			Body:       string(respBody),
+		}
+	}
+
+	return respBody, httpResp.Header, nil
+}
@@ -0,0 +1,51 @@
+package bitrise
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// DefaultSensitiveFields are the JSON fields and log keys whose values never
+// appear in debug logs.
+var DefaultSensitiveFields = []string{
+	"Authorization",
+	"authorization",
+	"token",
+	"value",
+	"auth_ssh_private_key",
+	"app_config_datastore_yaml",
+}
+
+var (
+	authorizationHeaderRegexp = regexp.MustCompile(`(?im)^(authorization:\s*).+$`)
+	privateKeyRegexp          = regexp.MustCompile(`-----BEGIN [A-Z0-9 ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z0-9 ]*PRIVATE KEY-----`)
+)
+
+// sensitiveFieldRegexp matches `"field": "..."` pairs of the given fields in
+// JSON payloads, including escaped quotes inside the value.
+func sensitiveFieldRegexp(fields []string) *regexp.Regexp {
+	pattern := `"(?:`
+	for i, field := range fields {
+		if i > 0 {
+			pattern += "|"
+		}
+		pattern += regexp.QuoteMeta(field)
+	}
+	pattern += `)"\s*:\s*"(?:[^"\\]|\\.)*"`
+
+	return regexp.MustCompile(pattern)
+}
+
+// LogContext returns ctx with tflog masking set up for the client's sensitive
+// fields. Log calls made with the returned context mask the values of those
+// keys, their occurrences inside JSON payloads and error bodies, private keys
+// and Authorization headers.
+func (c *Client) LogContext(ctx context.Context) context.Context {
+	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, c.sensitiveFields...)
+	ctx = tflog.MaskAllFieldValuesRegexes(ctx, c.sensitiveRegexps...)
+	ctx = tflog.MaskMessageRegexes(ctx, c.sensitiveRegexps...)
+
+	return ctx
+}
@@ -0,0 +1,27 @@
+package bitrise
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Group is a group of an organization.
+type Group struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+func organizationPath(orgSlug string) string {
+	return "/v0.1/organizations/" + url.PathEscape(orgSlug)
+}
+
+// ListOrganizationGroups returns the groups of an organization.
+func (c *Client) ListOrganizationGroups(ctx context.Context, orgSlug string) ([]Group, error) {
+	var out []Group
+	if err := c.do(ctx, http.MethodGet, organizationPath(orgSlug)+"/groups", nil, &out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
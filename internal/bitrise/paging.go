@@ -0,0 +1,47 @@
+package bitrise
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Paging is the pagination block of Bitrise list responses.
+type Paging struct {
+	TotalItemCount int    `json:"total_item_count"`
+	PageItemLimit  int    `json:"page_item_limit"`
+	Next           string `json:"next"`
+}
+
+type listResponse[T any] struct {
+	Data   []T    `json:"data"`
+	Paging Paging `json:"paging"`
+}
+
+// paginate walks a list endpoint by following the `next` cursor and hands
+// every page to fn.
+func paginate[T any](ctx context.Context, c *Client, path string, query url.Values, fn func(page []T)) error {
+	params := url.Values{}
+	for key, values := range query {
+		params[key] = values
+	}
+
+	for {
+		requestPath := path
+		if len(params) > 0 {
+			requestPath += "?" + params.Encode()
+		}
+
+		var out listResponse[T]
+		if err := c.do(ctx, http.MethodGet, requestPath, nil, &out); err != nil {
+			return err
+		}
+
+		fn(out.Data)
+
+		if out.Paging.Next == "" || out.Paging.Next == params.Get("next") {
+			return nil
+		}
+		params.Set("next", out.Paging.Next)
+	}
+}
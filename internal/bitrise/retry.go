@@ -0,0 +1,17 @@
+package bitrise
+
+import "context"
+
+type retryableKey struct{}
+
+// WithRetryable marks the requests sent with ctx as safe to retry even when
+// their HTTP method is not idempotent, e.g. a POST that replaces a document.
+func WithRetryable(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryableKey{}, true)
+}
+
+// IsRetryable reports whether ctx was marked with WithRetryable.
+func IsRetryable(ctx context.Context) bool {
+	retryable, _ := ctx.Value(retryableKey{}).(bool)
+	return retryable
+}
@@ -0,0 +1,34 @@
+package bitrise
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+type roleGroups struct {
+	Groups []string `json:"groups"`
+}
+
+func rolePath(appSlug, roleName string) string {
+	return appPath(appSlug) + "/roles/" + url.PathEscape(roleName)
+}
+
+// GetRoleGroups returns the group slugs assigned to a role of an app.
+func (c *Client) GetRoleGroups(ctx context.Context, appSlug, roleName string) ([]string, error) {
+	var out roleGroups
+	if err := c.do(ctx, http.MethodGet, rolePath(appSlug, roleName), nil, &out); err != nil {
+		return nil, err
+	}
+
+	return out.Groups, nil
+}
+
+// PutRoleGroups replaces the groups assigned to a role of an app.
+func (c *Client) PutRoleGroups(ctx context.Context, appSlug, roleName string, groups []string) error {
+	if groups == nil {
+		groups = []string{}
+	}
+
+	return c.do(ctx, http.MethodPut, rolePath(appSlug, roleName), roleGroups{Groups: groups}, nil)
+}
@@ -0,0 +1,138 @@
+package bitrise
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Secret is an app or workspace secret. Value is empty for protected
+// secrets. UpdatedAt is the time of the last write, empty when the API does
+// not report it.
+type Secret struct {
+	ID                       string `json:"id"`
+	Name                     string `json:"name"`
+	Value                    string `json:"value,omitempty"`
+	IsProtected              bool   `json:"is_protected"`
+	IsExposedForPullRequests bool   `json:"is_exposed_for_pull_requests"`
+	ExpandInStepInputs       bool   `json:"expand_in_step_inputs"`
+	UpdatedAt                string `json:"updated_at,omitempty"`
+}
+
+// CreateSecretParams is the payload of POST /v0.1/apps/{app-slug}/secrets and
+// of its workspace counterpart.
+type CreateSecretParams struct {
+	Name                     string `json:"name"`
+	Value                    string `json:"value"`
+	IsProtected              bool   `json:"is_protected,omitempty"`
+	IsExposedForPullRequests bool   `json:"is_exposed_for_pull_requests,omitempty"`
+	ExpandInStepInputs       bool   `json:"expand_in_step_inputs,omitempty"`
+}
+
+// UpdateSecretParams is the payload of PATCH
+// /v0.1/apps/{app-slug}/secrets/{name} and of its workspace counterpart.
+type UpdateSecretParams struct {
+	Value                    string `json:"value,omitempty"`
+	IsProtected              *bool  `json:"is_protected,omitempty"`
+	IsExposedForPullRequests *bool  `json:"is_exposed_for_pull_requests,omitempty"`
+	ExpandInStepInputs       *bool  `json:"expand_in_step_inputs,omitempty"`
+}
+
+func secretsPath(appSlug string) string {
+	return appPath(appSlug) + "/secrets"
+}
+
+func secretPath(appSlug, name string) string {
+	return secretsPath(appSlug) + "/" + url.PathEscape(name)
+}
+
+func workspaceSecretsPath(workspaceSlug string) string {
+	return organizationPath(workspaceSlug) + "/secrets"
+}
+
+func workspaceSecretPath(workspaceSlug, name string) string {
+	return workspaceSecretsPath(workspaceSlug) + "/" + url.PathEscape(name)
+}
+
+// ListSecrets returns every secret of an app, following pagination.
+func (c *Client) ListSecrets(ctx context.Context, appSlug string) ([]Secret, error) {
+	return c.listSecrets(ctx, secretsPath(appSlug))
+}
+
+// GetSecret returns a single secret of an app.
+func (c *Client) GetSecret(ctx context.Context, appSlug, name string) (*Secret, error) {
+	return c.getSecret(ctx, secretPath(appSlug, name))
+}
+
+// CreateSecret creates a secret on an app.
+func (c *Client) CreateSecret(ctx context.Context, appSlug string, params CreateSecretParams) (*Secret, error) {
+	return c.createSecret(ctx, secretsPath(appSlug), params)
+}
+
+// UpdateSecret updates the value and flags of a secret. The request is
+// retryable as it sets absolute values.
+func (c *Client) UpdateSecret(ctx context.Context, appSlug, name string, params UpdateSecretParams) error {
+	return c.do(WithRetryable(ctx), http.MethodPatch, secretPath(appSlug, name), params, nil)
+}
+
+// DeleteSecret deletes a secret from an app.
+func (c *Client) DeleteSecret(ctx context.Context, appSlug, name string) error {
+	return c.do(ctx, http.MethodDelete, secretPath(appSlug, name), nil, nil)
+}
+
+// ListWorkspaceSecrets returns every secret shared by the apps of a
+// workspace, following pagination.
+func (c *Client) ListWorkspaceSecrets(ctx context.Context, workspaceSlug string) ([]Secret, error) {
+	return c.listSecrets(ctx, workspaceSecretsPath(workspaceSlug))
+}
+
+// GetWorkspaceSecret returns a single secret of a workspace.
+func (c *Client) GetWorkspaceSecret(ctx context.Context, workspaceSlug, name string) (*Secret, error) {
+	return c.getSecret(ctx, workspaceSecretPath(workspaceSlug, name))
+}
+
+// CreateWorkspaceSecret creates a secret on a workspace.
+func (c *Client) CreateWorkspaceSecret(ctx context.Context, workspaceSlug string, params CreateSecretParams) (*Secret, error) {
+	return c.createSecret(ctx, workspaceSecretsPath(workspaceSlug), params)
+}
+
+// UpdateWorkspaceSecret updates the value and flags of a workspace secret.
+// The request is retryable as it sets absolute values.
+func (c *Client) UpdateWorkspaceSecret(ctx context.Context, workspaceSlug, name string, params UpdateSecretParams) error {
+	return c.do(WithRetryable(ctx), http.MethodPatch, workspaceSecretPath(workspaceSlug, name), params, nil)
+}
+
+// DeleteWorkspaceSecret deletes a secret from a workspace.
+func (c *Client) DeleteWorkspaceSecret(ctx context.Context, workspaceSlug, name string) error {
+	return c.do(ctx, http.MethodDelete, workspaceSecretPath(workspaceSlug, name), nil, nil)
+}
+
+func (c *Client) listSecrets(ctx context.Context, path string) ([]Secret, error) {
+	var secrets []Secret
+	err := paginate(ctx, c, path, nil, func(page []Secret) {
+		secrets = append(secrets, page...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return secrets, nil
+}
+
+func (c *Client) getSecret(ctx context.Context, path string) (*Secret, error) {
+	var out Secret
+	if err := c.do(ctx, http.MethodGet, path, nil, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+func (c *Client) createSecret(ctx context.Context, path string, params CreateSecretParams) (*Secret, error) {
+	var out Secret
+	if err := c.do(ctx, http.MethodPost, path, params, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
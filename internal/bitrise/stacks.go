@@ -0,0 +1,60 @@
+package bitrise
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Stack describes a build stack as returned by GET /v0.1/available-stacks.
+// Only the title and project types are always present, the other fields are
+// empty when the API does not report them.
+type Stack struct {
+	Title           string   `json:"title"`
+	ProjectTypes    []string `json:"project_types"`
+	OS              string   `json:"os"`
+	DeprecationDate string   `json:"deprecation_date"`
+	RemovalDate     string   `json:"removal_date"`
+	MachineTypes    []string `json:"machine_types"`
+}
+
+// StackOS returns the operating system of the stack with the given ID,
+// falling back to the ID prefix when the API does not report it.
+func StackOS(id string, stack Stack) string {
+	if stack.OS != "" {
+		return strings.ToLower(stack.OS)
+	}
+
+	switch {
+	case strings.HasPrefix(id, "osx-"), strings.HasPrefix(id, "macos-"):
+		return "osx"
+	case strings.HasPrefix(id, "linux-"), strings.HasPrefix(id, "ubuntu-"):
+		return "linux"
+	}
+
+	return ""
+}
+
+// ListAvailableStacks returns the available stacks keyed by stack ID. The
+// list is fetched once and cached for the lifetime of the client, so plan time
+// validation does not hit the API for every resource. The returned map is
+// shared and must not be modified.
+func (c *Client) ListAvailableStacks(ctx context.Context) (map[string]Stack, error) {
+	c.stacksMu.Lock()
+	defer c.stacksMu.Unlock()
+
+	if c.stacks != nil {
+		return c.stacks, nil
+	}
+
+	var out map[string]Stack
+	if err := c.do(ctx, http.MethodGet, "/v0.1/available-stacks", nil, &out); err != nil {
+		return nil, err
+	}
+	if out == nil {
+		out = map[string]Stack{}
+	}
+	c.stacks = out
+
+	return out, nil
+}
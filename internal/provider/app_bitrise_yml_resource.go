@@ -2,11 +2,9 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-	"strings"
+
+	"terraform-provider-bitrise/internal/bitrise"
 
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -20,35 +18,21 @@ import (
 var _ resource.Resource = &AppBitriseYmlResource{}
 var _ resource.ResourceWithImportState = &AppBitriseYmlResource{}
 
-func NewAppBitriseYmlResource(clientCreator func(endpoint, token string) *http.Client, endpoint, token string) *AppBitriseYmlResource {
-	return &AppBitriseYmlResource{
-		clientCreator: clientCreator,
-		endpoint:      endpoint,
-		token:         token,
-	}
+func NewAppBitriseYmlResource() resource.Resource {
+	return &AppBitriseYmlResource{}
 }
 
 type AppBitriseYmlResource struct {
-	clientCreator func(endpoint, token string) *http.Client
-	endpoint      string
-	token         string
+	client *bitrise.Client
 }
 
 type AppBitriseYmlResourceModel struct {
 	AppSlug            types.String `tfsdk:"app_slug"`
-	YmlContent         types.String `tfsdk:"yml_content"`
+	YmlContent         yamlString   `tfsdk:"yml_content"`
 	UpdateOnCreateOnly types.Bool   `tfsdk:"update_on_create_only"`
 	ID                 types.String `tfsdk:"id"`
 }
 
-type BitriseYmlRequest struct {
-	AppConfigDatastoreYaml string `json:"app_config_datastore_yaml"`
-}
-
-type BitriseYmlResponse struct {
-	AppConfigDatastoreYaml string `json:"app_config_datastore_yaml"`
-}
-
 // ignoreChangesIfUpdateOnCreateOnly is a custom plan modifier that prevents updates when update_on_create_only is true
 type ignoreChangesIfUpdateOnCreateOnly struct{}
 
@@ -95,7 +79,8 @@ func (r *AppBitriseYmlResource) Schema(ctx context.Context, req resource.SchemaR
 				},
 			},
 			"yml_content": schema.StringAttribute{
-				MarkdownDescription: "The content of the bitrise.yml file. This should be a valid YAML configuration for Bitrise workflows.",
+				MarkdownDescription: "The content of the bitrise.yml file. This should be a valid YAML configuration for Bitrise workflows. It is compared by content, so formatting, key order and comments do not cause changes.",
+				CustomType:          yamlStringType{},
 				Required:            true,
 				PlanModifiers: []planmodifier.String{
 					ignoreChangesIfUpdateOnCreateOnly{},
@@ -121,19 +106,21 @@ func (r *AppBitriseYmlResource) Configure(ctx context.Context, req resource.Conf
 		return
 	}
 
-	clientCreator, ok := req.ProviderData.(func(endpoint, token string) *http.Client)
+	client, ok := req.ProviderData.(*bitrise.Client)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected func(endpoint, token string) *http.Client, got: %T", req.ProviderData),
+			fmt.Sprintf("Expected *bitrise.Client, got: %T", req.ProviderData),
 		)
 		return
 	}
 
-	r.clientCreator = clientCreator
+	r.client = client
 }
 
 func (r *AppBitriseYmlResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.LogContext(ctx)
+
 	var data AppBitriseYmlResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
@@ -144,72 +131,13 @@ func (r *AppBitriseYmlResource) Create(ctx context.Context, req resource.CreateR
 		"app_slug": data.AppSlug.ValueString(),
 	})
 
-	client := r.clientCreator(r.endpoint, r.token)
 	appSlug := data.AppSlug.ValueString()
 	ymlContent := data.YmlContent.ValueString()
 
-	// Prepare request payload
-	payload := BitriseYmlRequest{
-		AppConfigDatastoreYaml: ymlContent,
-	}
-
-	payloadJSON, err := json.Marshal(payload)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error marshaling request payload",
-			fmt.Sprintf("Could not marshal payload: %s", err.Error()),
-		)
-		return
-	}
-
-	// Create the PUT request
-	url := fmt.Sprintf("%s/v0.1/apps/%s/bitrise.yml", r.endpoint, appSlug)
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, strings.NewReader(string(payloadJSON)))
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating HTTP request",
-			fmt.Sprintf("Could not create request: %s", err.Error()),
-		)
-		return
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	tflog.Debug(ctx, "Sending HTTP request", map[string]interface{}{
-		"method": "POST",
-		"url":    url,
-	})
-
-	// Send the request
-	httpResp, err := client.Do(httpReq)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error sending HTTP request",
-			fmt.Sprintf("Could not send request: %s", err.Error()),
-		)
-		return
-	}
-	defer httpResp.Body.Close()
-
-	// Read response body for debugging
-	responseBody, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error reading response body",
-			fmt.Sprintf("Could not read response: %s", err.Error()),
-		)
-		return
-	}
-
-	tflog.Debug(ctx, "Received HTTP response", map[string]interface{}{
-		"status": httpResp.StatusCode,
-		"body":   string(responseBody),
-	})
-
-	if httpResp.StatusCode != http.StatusOK && httpResp.StatusCode != http.StatusCreated {
+	if err := r.client.UpdateBitriseYML(ctx, appSlug, ymlContent); err != nil {
 		resp.Diagnostics.AddError(
 			"API Request Error",
-			fmt.Sprintf("Request failed with status %d: %s", httpResp.StatusCode, string(responseBody)),
+			fmt.Sprintf("Could not upload bitrise.yml: %s", err.Error()),
 		)
 		return
 	}
@@ -222,6 +150,8 @@ func (r *AppBitriseYmlResource) Create(ctx context.Context, req resource.CreateR
 }
 
 func (r *AppBitriseYmlResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.LogContext(ctx)
+
 	var data AppBitriseYmlResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
@@ -232,91 +162,24 @@ func (r *AppBitriseYmlResource) Read(ctx context.Context, req resource.ReadReque
 		"app_slug": data.AppSlug.ValueString(),
 	})
 
-	client := r.clientCreator(r.endpoint, r.token)
 	appSlug := data.AppSlug.ValueString()
 
-	// Create the GET request
-	url := fmt.Sprintf("%s/v0.1/apps/%s/bitrise.yml", r.endpoint, appSlug)
-	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating HTTP request",
-			fmt.Sprintf("Could not create request: %s", err.Error()),
-		)
-		return
-	}
-
-	tflog.Debug(ctx, "Sending HTTP request", map[string]interface{}{
-		"method": "GET",
-		"url":    url,
-	})
-
-	// Send the request
-	httpResp, err := client.Do(httpReq)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error sending HTTP request",
-			fmt.Sprintf("Could not send request: %s", err.Error()),
-		)
-		return
-	}
-	defer httpResp.Body.Close()
-
-	// Read response body
-	responseBody, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error reading response body",
-			fmt.Sprintf("Could not read response: %s", err.Error()),
-		)
-		return
-	}
-
-	tflog.Debug(ctx, "Received HTTP response", map[string]interface{}{
-		"status":       httpResp.StatusCode,
-		"content-type": httpResp.Header.Get("Content-Type"),
-		"body":         string(responseBody),
-	})
-
-	if httpResp.StatusCode == http.StatusNotFound {
+	ymlContent, err := r.client.GetBitriseYML(ctx, appSlug)
+	if bitrise.IsNotFound(err) {
 		tflog.Warn(ctx, "Bitrise.yml not found, removing from state")
 		resp.State.RemoveResource(ctx)
 		return
 	}
-
-	if httpResp.StatusCode != http.StatusOK {
+	if err != nil {
 		resp.Diagnostics.AddError(
 			"API Request Error",
-			fmt.Sprintf("Request failed with status %d: %s", httpResp.StatusCode, string(responseBody)),
+			fmt.Sprintf("Could not read bitrise.yml: %s", err.Error()),
 		)
 		return
 	}
 
-	// Parse response - handle both JSON and plain text responses
-	var ymlContent string
-	contentType := httpResp.Header.Get("Content-Type")
-	
-	// Try to parse as JSON first
-	if strings.Contains(contentType, "application/json") || len(responseBody) > 0 && responseBody[0] == '{' {
-		var ymlResponse BitriseYmlResponse
-		err = json.Unmarshal(responseBody, &ymlResponse)
-		if err != nil {
-			tflog.Debug(ctx, "Failed to parse as JSON, treating as plain text", map[string]interface{}{
-				"error": err.Error(),
-			})
-			// If JSON parsing fails, treat the response as plain YAML content
-			ymlContent = string(responseBody)
-		} else {
-			ymlContent = ymlResponse.AppConfigDatastoreYaml
-		}
-	} else {
-		// Response is likely plain text/YAML
-		tflog.Debug(ctx, "Response appears to be plain text, not JSON")
-		ymlContent = string(responseBody)
-	}
-
 	// Update state with current values
-	data.YmlContent = types.StringValue(ymlContent)
+	data.YmlContent = newYAMLStringValue(ymlContent)
 	data.ID = data.AppSlug
 
 	tflog.Info(ctx, "Successfully read bitrise.yml")
@@ -324,6 +187,8 @@ func (r *AppBitriseYmlResource) Read(ctx context.Context, req resource.ReadReque
 }
 
 func (r *AppBitriseYmlResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.client.LogContext(ctx)
+
 	var data AppBitriseYmlResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
@@ -344,72 +209,14 @@ func (r *AppBitriseYmlResource) Update(ctx context.Context, req resource.UpdateR
 		"app_slug": data.AppSlug.ValueString(),
 	})
 
-	client := r.clientCreator(r.endpoint, r.token)
 	appSlug := data.AppSlug.ValueString()
 	ymlContent := data.YmlContent.ValueString()
 
-	// Prepare request payload
-	payload := BitriseYmlRequest{
-		AppConfigDatastoreYaml: ymlContent,
-	}
-
-	payloadJSON, err := json.Marshal(payload)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error marshaling request payload",
-			fmt.Sprintf("Could not marshal payload: %s", err.Error()),
-		)
-		return
-	}
-
-	// Create the POST request (Bitrise API uses POST for updates)
-	url := fmt.Sprintf("%s/v0.1/apps/%s/bitrise.yml", r.endpoint, appSlug)
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, strings.NewReader(string(payloadJSON)))
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating HTTP request",
-			fmt.Sprintf("Could not create request: %s", err.Error()),
-		)
-		return
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	tflog.Debug(ctx, "Sending HTTP request", map[string]interface{}{
-		"method": "POST",
-		"url":    url,
-	})
-
-	// Send the request
-	httpResp, err := client.Do(httpReq)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error sending HTTP request",
-			fmt.Sprintf("Could not send request: %s", err.Error()),
-		)
-		return
-	}
-	defer httpResp.Body.Close()
-
-	// Read response body for debugging
-	responseBody, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error reading response body",
-			fmt.Sprintf("Could not read response: %s", err.Error()),
-		)
-		return
-	}
-
-	tflog.Debug(ctx, "Received HTTP response", map[string]interface{}{
-		"status": httpResp.StatusCode,
-		"body":   string(responseBody),
-	})
-
-	if httpResp.StatusCode != http.StatusOK && httpResp.StatusCode != http.StatusCreated {
+	// Bitrise API uses POST for updates
+	if err := r.client.UpdateBitriseYML(ctx, appSlug, ymlContent); err != nil {
 		resp.Diagnostics.AddError(
 			"API Request Error",
-			fmt.Sprintf("Request failed with status %d: %s", httpResp.StatusCode, string(responseBody)),
+			fmt.Sprintf("Could not upload bitrise.yml: %s", err.Error()),
 		)
 		return
 	}
@@ -422,6 +229,8 @@ func (r *AppBitriseYmlResource) Update(ctx context.Context, req resource.UpdateR
 }
 
 func (r *AppBitriseYmlResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.client.LogContext(ctx)
+
 	var data AppBitriseYmlResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
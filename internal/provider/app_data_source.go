@@ -3,16 +3,21 @@ package provider
 import (
 	"context"
 	"fmt"
-	"net/http"
+	"strings"
 
+	"terraform-provider-bitrise/internal/bitrise"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ datasource.DataSource = &AppDataSource{}
+var _ datasource.DataSourceWithConfigValidators = &AppDataSource{}
 
 func NewAppDataSource() datasource.DataSource {
 	return &AppDataSource{}
@@ -20,14 +25,32 @@ func NewAppDataSource() datasource.DataSource {
 
 // AppDataSource defines the data source implementation.
 type AppDataSource struct {
-	client *http.Client
+	client *bitrise.Client
 }
 
 // AppDataSourceModel describes the data source data model.
 type AppDataSourceModel struct {
-	ConfigurableAttribute types.String `tfsdk:"configurable_attribute"`
-	Id                    types.String `tfsdk:"id"`
-	AppSlug               types.String `tfsdk:"app_slug"`
+	ID               types.String   `tfsdk:"id"`
+	AppSlug          types.String   `tfsdk:"app_slug"`
+	OrganizationSlug types.String   `tfsdk:"organization_slug"`
+	RepoURL          types.String   `tfsdk:"repo_url"`
+	Title            types.String   `tfsdk:"title"`
+	ProjectType      types.String   `tfsdk:"project_type"`
+	Repo             types.String   `tfsdk:"repo"`
+	RepoOwner        types.String   `tfsdk:"repo_owner"`
+	RepoSlug         types.String   `tfsdk:"repo_slug"`
+	IsPublic         types.Bool     `tfsdk:"is_public"`
+	IsDisabled       types.Bool     `tfsdk:"is_disabled"`
+	Status           types.Int64    `tfsdk:"status"`
+	Owner            *AppOwnerModel `tfsdk:"owner"`
+	Stack            types.String   `tfsdk:"stack"`
+}
+
+// AppOwnerModel describes the account owning an app.
+type AppOwnerModel struct {
+	AccountType types.String `tfsdk:"account_type"`
+	Name        types.String `tfsdk:"name"`
+	Slug        types.String `tfsdk:"slug"`
 }
 
 func (d *AppDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -37,39 +60,110 @@ func (d *AppDataSource) Metadata(ctx context.Context, req datasource.MetadataReq
 func (d *AppDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		// This description is used by the documentation generator and the language server.
-		MarkdownDescription: "App data source",
+		MarkdownDescription: "Looks up a Bitrise app either by its slug or by the repository URL within an organization.",
 
 		Attributes: map[string]schema.Attribute{
-			"configurable_attribute": schema.StringAttribute{
-				MarkdownDescription: "App configurable attribute",
-				Optional:            true,
-			},
 			"id": schema.StringAttribute{
-				MarkdownDescription: "App identifier",
+				MarkdownDescription: "App identifier (app_slug)",
 				Computed:            true,
 			},
 			"app_slug": schema.StringAttribute{
-				MarkdownDescription: "App slug",
+				MarkdownDescription: "Slug of the app to look up. Conflicts with `repo_url`.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"organization_slug": schema.StringAttribute{
+				MarkdownDescription: "Slug of the organization to search for `repo_url` in",
+				Optional:            true,
+			},
+			"repo_url": schema.StringAttribute{
+				MarkdownDescription: "Repository URL of the app to look up, requires `organization_slug`. HTTPS and SSH forms of the same repository match each other.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"title": schema.StringAttribute{
+				MarkdownDescription: "Title of the app",
+				Computed:            true,
+			},
+			"project_type": schema.StringAttribute{
+				MarkdownDescription: "Project type of the app",
+				Computed:            true,
+			},
+			"repo": schema.StringAttribute{
+				MarkdownDescription: "Git provider of the repository (e.g. github)",
+				Computed:            true,
+			},
+			"repo_owner": schema.StringAttribute{
+				MarkdownDescription: "Owner of the repository",
+				Computed:            true,
+			},
+			"repo_slug": schema.StringAttribute{
+				MarkdownDescription: "Slug of the repository",
+				Computed:            true,
+			},
+			"is_public": schema.BoolAttribute{
+				MarkdownDescription: "Whether the app is public",
+				Computed:            true,
+			},
+			"is_disabled": schema.BoolAttribute{
+				MarkdownDescription: "Whether the app is disabled",
+				Computed:            true,
+			},
+			"status": schema.Int64Attribute{
+				MarkdownDescription: "Status of the app as reported by the API",
+				Computed:            true,
+			},
+			"owner": schema.SingleNestedAttribute{
+				MarkdownDescription: "Account owning the app",
+				Computed:            true,
+				Attributes: map[string]schema.Attribute{
+					"account_type": schema.StringAttribute{
+						MarkdownDescription: "Type of the owner account, `organization` or `user`",
+						Computed:            true,
+					},
+					"name": schema.StringAttribute{
+						MarkdownDescription: "Name of the owner",
+						Computed:            true,
+					},
+					"slug": schema.StringAttribute{
+						MarkdownDescription: "Slug of the owner",
+						Computed:            true,
+					},
+				},
+			},
+			"stack": schema.StringAttribute{
+				MarkdownDescription: "Default stack of the app from its bitrise.yml, empty if not set",
 				Computed:            true,
 			},
 		},
 	}
 }
 
+func (d *AppDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("app_slug"),
+			path.MatchRoot("repo_url"),
+		),
+		datasourcevalidator.RequiredTogether(
+			path.MatchRoot("organization_slug"),
+			path.MatchRoot("repo_url"),
+		),
+	}
+}
+
 func (d *AppDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*http.Client)
-
+	client, ok := req.ProviderData.(*bitrise.Client)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *bitrise.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
-
 		return
 	}
 
@@ -77,34 +171,144 @@ func (d *AppDataSource) Configure(ctx context.Context, req datasource.ConfigureR
 }
 
 func (d *AppDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
-	var data AppDataSourceModel
+	ctx = d.client.LogContext(ctx)
 
-	// Read the app_slug value from the state of bitrise_app resource
-	resp.Diagnostics.Append(req.Config.Get(ctx, &data.AppSlug)...)
+	var data AppDataSourceModel
 
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider client data and make a call using it.
-	// httpResp, err := d.client.Do(httpReq)
-	// if err != nil {
-	//     resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read example, got error: %s", err))
-	//     return
-	// }
+	var app *bitrise.App
+	if !data.AppSlug.IsNull() {
+		tflog.Debug(ctx, "Reading Bitrise app", map[string]interface{}{"app_slug": data.AppSlug.ValueString()})
+
+		var err error
+		app, err = d.client.GetApp(ctx, data.AppSlug.ValueString())
+		if err != nil {
+			tflog.Error(ctx, "Failed to read app", map[string]interface{}{"error": err.Error()})
+			resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to read app: %s", err))
+			return
+		}
+	} else {
+		app = d.findAppByRepoURL(ctx, data.OrganizationSlug.ValueString(), data.RepoURL.ValueString(), resp)
+		if app == nil {
+			return
+		}
+	}
+
+	stack := ""
+	yml, err := d.client.GetBitriseYML(ctx, app.Slug)
+	switch {
+	case bitrise.IsNotFound(err):
+		// Apps that were never finished have no bitrise.yml yet
+	case err != nil:
+		tflog.Error(ctx, "Failed to read bitrise.yml", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to read bitrise.yml: %s", err))
+		return
+	default:
+		if config, err := bitrise.ParseAppConfig(yml); err == nil {
+			stack = config.Stack
+		} else {
+			resp.Diagnostics.AddWarning("Unable to Read Stack", fmt.Sprintf("The bitrise.yml of app %s could not be parsed: %s", app.Slug, err))
+		}
+	}
 
-	// For the purposes of this example code, hardcoding a response value to
-	// save into the Terraform state.
-	data.Id = types.StringValue("example-id")
+	data.ID = types.StringValue(app.Slug)
+	data.AppSlug = types.StringValue(app.Slug)
+	data.RepoURL = types.StringValue(app.RepoURL)
+	data.Title = types.StringValue(app.Title)
+	data.ProjectType = types.StringValue(app.ProjectType)
+	data.Repo = types.StringValue(app.Provider)
+	data.RepoOwner = types.StringValue(app.RepoOwner)
+	data.RepoSlug = types.StringValue(app.RepoSlug)
+	data.IsPublic = types.BoolValue(app.IsPublic)
+	data.IsDisabled = types.BoolValue(app.IsDisabled)
+	data.Status = types.Int64Value(int64(app.Status))
+	data.Owner = &AppOwnerModel{
+		AccountType: types.StringValue(app.Owner.AccountType),
+		Name:        types.StringValue(app.Owner.Name),
+		Slug:        types.StringValue(app.Owner.Slug),
+	}
+	data.Stack = types.StringValue(stack)
 
-	// Write logs using the tflog package
-	// Documentation: https://terraform.io/plugin/log
-	tflog.Trace(ctx, "read a data source")
-	data.AppSlug = types.StringValue(data.AppSlug.String())
+	tflog.Debug(ctx, "Successfully read Bitrise app", map[string]interface{}{"app_slug": app.Slug})
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
-	resp.State.Set(ctx, &data)
+}
+
+// findAppByRepoURL returns the single app of the organization connected to
+// repoURL, reporting an error when none or several match.
+func (d *AppDataSource) findAppByRepoURL(ctx context.Context, orgSlug, repoURL string, resp *datasource.ReadResponse) *bitrise.App {
+	tflog.Debug(ctx, "Looking up Bitrise app by repository URL", map[string]interface{}{
+		"organization_slug": orgSlug,
+		"repo_url":          repoURL,
+	})
+
+	apps, err := d.client.ListOrganizationApps(ctx, orgSlug, bitrise.ListAppsParams{})
+	if err != nil {
+		tflog.Error(ctx, "Failed to list organization apps", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to list apps of organization %s: %s", orgSlug, err))
+		return nil
+	}
+
+	want := normalizeRepoURL(repoURL)
+	var matches []bitrise.App
+	for _, app := range apps {
+		if normalizeRepoURL(app.RepoURL) == want {
+			matches = append(matches, app)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		resp.Diagnostics.AddAttributeError(path.Root("repo_url"), "App Not Found",
+			fmt.Sprintf("No app of organization %s is connected to %s.", orgSlug, repoURL))
+		return nil
+	case 1:
+		return &matches[0]
+	}
+
+	slugs := make([]string, 0, len(matches))
+	for _, app := range matches {
+		slugs = append(slugs, app.Slug)
+	}
+	resp.Diagnostics.AddAttributeError(path.Root("repo_url"), "Multiple Apps Found",
+		fmt.Sprintf("Several apps of organization %s are connected to %s: %s. Use app_slug to pick one.", orgSlug, repoURL, strings.Join(slugs, ", ")))
+	return nil
+}
+
+// normalizeRepoURL reduces a repository URL to host/path so that the HTTPS
+// and SSH forms of the same repository compare equal.
+func normalizeRepoURL(repoURL string) string {
+	u := strings.ToLower(strings.TrimSpace(repoURL))
+
+	hasScheme := false
+	for _, scheme := range []string{"https://", "http://", "ssh://", "git://"} {
+		if strings.HasPrefix(u, scheme) {
+			u = strings.TrimPrefix(u, scheme)
+			hasScheme = true
+		}
+	}
+
+	// Drop credentials or the git@ user
+	if at := strings.Index(u, "@"); at >= 0 && at < strings.Index(u+"/", "/") {
+		u = u[at+1:]
+	}
+
+	// Port (ssh://host:22/owner/repo) or scp-like syntax (host:owner/repo),
+	// the latter has no port so a numeric owner is kept
+	if colon := strings.Index(u, ":"); colon >= 0 && colon < strings.Index(u+"/", "/") {
+		rest := u[colon+1:]
+		if port := strings.TrimLeft(rest, "0123456789"); hasScheme && port != rest && strings.HasPrefix(port, "/") {
+			rest = port[1:]
+		}
+		u = u[:colon] + "/" + rest
+	}
+
+	u = strings.TrimSuffix(strings.TrimSuffix(u, "/"), ".git")
 
+	return u
 }
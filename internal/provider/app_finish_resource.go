@@ -2,13 +2,15 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-	"net/http/httputil"
+	"maps"
 	"strings"
+	"time"
 
+	"terraform-provider-bitrise/internal/bitrise"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -16,37 +18,35 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
+var _ resource.Resource = &AppFinishResource{}
+var _ resource.ResourceWithImportState = &AppFinishResource{}
+var _ resource.ResourceWithModifyPlan = &AppFinishResource{}
+
 type AppFinishResource struct {
-	clientCreator func(endpoint, token string) *http.Client
-	endpoint      string
-	token         string
+	client *bitrise.Client
 }
 
 type AppFinishResourceModel struct {
-	AppSlug          string            `tfsdk:"app_slug"`
-	ProjectType      string            `tfsdk:"project_type"`
-	StackID          string            `tfsdk:"stack_id"`
-	Config           string            `tfsdk:"config"`
-	Mode             string            `tfsdk:"mode"`
+	AppSlug          types.String      `tfsdk:"app_slug"`
+	ProjectType      types.String      `tfsdk:"project_type"`
+	StackID          types.String      `tfsdk:"stack_id"`
+	Config           types.String      `tfsdk:"config"`
+	Mode             types.String      `tfsdk:"mode"`
 	Envs             map[string]string `tfsdk:"envs"`
-	OrganizationSlug string            `tfsdk:"organization_slug"`
+	OrganizationSlug types.String      `tfsdk:"organization_slug"`
+	Timeouts         timeouts.Value    `tfsdk:"timeouts"`
 }
 
-type PayloadFinish struct {
-	ProjectType      string            `json:"project_type"`
-	StackID          string            `json:"stack_id"`
-	Config           string            `json:"config"`
-	Mode             string            `json:"mode"`
-	Envs             map[string]string `json:"envs"`
-	OrganizationSlug string            `json:"organization_slug"`
-}
+// appFinishImportedKey is the private state key marking an imported resource
+// whose config and mode, taken from the import ID, have not been applied yet.
+const appFinishImportedKey = "imported"
 
-func NewAppFinishResource(clientCreator func(endpoint, token string) *http.Client, endpoint, token string) *AppFinishResource {
-	return &AppFinishResource{
-		clientCreator: clientCreator,
-		endpoint:      endpoint,
-		token:         token,
-	}
+// defaultAppFinishTimeout bounds the /finish call and the wait for the app to
+// become active.
+const defaultAppFinishTimeout = 10 * time.Minute
+
+func NewAppFinishResource() resource.Resource {
+	return &AppFinishResource{}
 }
 
 func (r *AppFinishResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -87,6 +87,12 @@ func (r *AppFinishResource) Schema(ctx context.Context, req resource.SchemaReque
 				Required:            true,
 			},
 		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+			}),
+		},
 	}
 }
 
@@ -95,112 +101,127 @@ func (r *AppFinishResource) Configure(ctx context.Context, req resource.Configur
 		tflog.Debug(ctx, "MODULEDEBUG: Provider data is missing")
 		return
 	}
-	clientCreator, ok := req.ProviderData.(func(endpoint, token string) *http.Client)
+	client, ok := req.ProviderData.(*bitrise.Client)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *bitrise.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	r.clientCreator = clientCreator
+	r.client = client
 	tflog.Debug(ctx, "MODULEDEBUG: Provider configuration successful")
 }
 
-func (r *AppFinishResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	// Initialize data to store API response
-	var data AppFinishResourceModel
-
-	// Populate data from Terraform plan
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
-
-	// Check for errors in diagnostics and return if found
-	if resp.Diagnostics.HasError() {
+// ModifyPlan checks a new or changed stack_id against the available stacks, so
+// typos and removed stacks are reported at plan time instead of by /finish.
+func (r *AppFinishResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on destroy or before the provider is configured
+	if req.Plan.Raw.IsNull() || r.client == nil {
 		return
 	}
 
-	tflog.Debug(ctx, "MODULEDEBUG: Starting AppFinishResource Create")
+	var stackID types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("stack_id"), &stackID)...)
+	if resp.Diagnostics.HasError() || stackID.IsUnknown() || stackID.IsNull() {
+		return
+	}
 
-	// Create an HTTP client using the client creator from the provider
-	client := r.clientCreator(r.endpoint, r.token)
+	// Stacks removed after the app was set up must not block unrelated changes
+	if !req.State.Raw.IsNull() {
+		var stateStackID types.String
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("stack_id"), &stateStackID)...)
+		if resp.Diagnostics.HasError() || stateStackID.Equal(stackID) {
+			return
+		}
+	}
 
-	// Construct the URL for creating the request
-	appSlug := data.AppSlug
-	completeURL := fmt.Sprintf("%s/v0.1/apps/%s/finish", r.endpoint, appSlug)
+	validateStackID(ctx, r.client, stackID.ValueString(), path.Root("stack_id"), &resp.Diagnostics)
+}
 
-	// Construct the payload data using the provided variables
-	payload := PayloadFinish{
-		ProjectType:      data.ProjectType,
-		StackID:          data.StackID,
-		Config:           data.Config,
-		Mode:             data.Mode,
-		Envs:             data.Envs,
-		OrganizationSlug: data.OrganizationSlug,
+// validateStackID reports an error on attrPath when stackID is not one of the
+// available stacks, suggesting the closest stack IDs. It stays silent when the
+// stacks cannot be listed, so an API hiccup never blocks a plan.
+func validateStackID(ctx context.Context, client *bitrise.Client, stackID string, attrPath path.Path, diags *diag.Diagnostics) {
+	stacks, err := client.ListAvailableStacks(ctx)
+	if err != nil {
+		tflog.Warn(ctx, "Unable to list available stacks, skipping stack_id validation", map[string]interface{}{"error": err.Error()})
+		return
 	}
 
-	// Marshal the payload struct into a JSON string
-	payloadJSON, err := json.Marshal(payload)
-	if err != nil {
-		tflog.Error(ctx, "Error marshaling JSON payload", map[string]interface{}{"error": err.Error()})
-		handleRequestError(err, resp)
+	if _, ok := stacks[stackID]; ok {
 		return
 	}
 
-	tflog.Debug(ctx, "MODULEDEBUG: Request payload", map[string]interface{}{"payload_json": string(payloadJSON)})
+	stackIDs := make([]string, 0, len(stacks))
+	for id := range stacks {
+		stackIDs = append(stackIDs, id)
+	}
 
-	// Create an HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", completeURL, strings.NewReader(string(payloadJSON)))
-	if err != nil {
-		tflog.Error(ctx, "Error creating HTTP request", map[string]interface{}{"error": err.Error()})
-		handleRequestError(err, resp)
+	detail := fmt.Sprintf("Stack %q is not available on Bitrise.", stackID)
+	if suggestions := closestMatches(stackID, stackIDs, 3); len(suggestions) > 0 {
+		detail += fmt.Sprintf(" Did you mean one of: %s?", strings.Join(suggestions, ", "))
+	}
+	detail += " The bitrise_available_stacks data source lists every available stack."
+
+	diags.AddAttributeError(attrPath, "Invalid Stack ID", detail)
+}
+
+func (r *AppFinishResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.LogContext(ctx)
+
+	// Initialize data to store API response
+	var data AppFinishResourceModel
+
+	// Populate data from Terraform plan
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	// Check for errors in diagnostics and return if found
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	// Set headers
-	httpReq.Header.Set("Content-Type", "application/json")
 
-	// Dump the HTTP request details
-	dump, _ := httputil.DumpRequest(httpReq, true)
-	tflog.Debug(ctx, "MODULEDEBUG: HTTP Request", map[string]interface{}{"request": string(dump)})
+	tflog.Debug(ctx, "MODULEDEBUG: Starting AppFinishResource Create")
 
-	// Send the HTTP request
-	httpResp, err := client.Do(httpReq)
-	if err != nil {
-		tflog.Error(ctx, "Error sending HTTP request", map[string]interface{}{"error": err.Error()})
-		handleRequestError(err, resp)
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultAppFinishTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	defer httpResp.Body.Close()
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
 
-	// Read the response body
-	responseBody, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		tflog.Error(ctx, "Error reading response body", map[string]interface{}{"error": err.Error()})
-		handleRequestError(err, resp)
+	if err := r.client.FinishApp(ctx, data.AppSlug.ValueString(), finishParams(data)); err != nil {
+		tflog.Error(ctx, "MODULEDEBUG: Request did not succeed", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Request Error", fmt.Sprintf("Unable to finish app setup: %s", err))
 		return
 	}
-	tflog.Debug(ctx, "MODULEDEBUG: Response body", map[string]interface{}{"body": string(responseBody)})
 
-	// Debugging: Print response status and headers
-	printResponseInfo(httpResp)
+	// The setup is submitted, so the state is saved before waiting. A failed
+	// wait then leaves the resource tainted instead of forgetting the setup.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	if httpResp.StatusCode != http.StatusOK {
-		tflog.Error(ctx, "MODULEDEBUG: Request did not succeed", map[string]interface{}{
-			"status": httpResp.Status,
-			"headers": httpResp.Header,
-		})
-		resp.Diagnostics.AddError("MODULEDEBUG: API Request Error", fmt.Sprintf("Request did not succeed: %s", httpResp.Status))
+	if !waitForActiveApp(ctx, r.client, data.AppSlug.ValueString(), &resp.Diagnostics) {
+		resp.Diagnostics.AddError(
+			"App Setup Submitted",
+			fmt.Sprintf("The setup of app %s was submitted and is saved as tainted. "+
+				"Run `terraform untaint` on this resource to keep it once the app is active, "+
+				"otherwise the next apply submits the setup again.", data.AppSlug.ValueString()),
+		)
 		return
 	}
 
 	tflog.Info(ctx, "MODULEDEBUG: App registration completed successfully")
-
-	// Update resource state with populated data
-	resp.State.Set(ctx, &data)
 }
 
 func (r *AppFinishResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.LogContext(ctx)
+
 	var data AppFinishResourceModel
 
 	// Read Terraform prior state data into the model
@@ -212,67 +233,82 @@ func (r *AppFinishResource) Read(ctx context.Context, req resource.ReadRequest,
 
 	tflog.Debug(ctx, "MODULEDEBUG: Starting AppFinishResource Read")
 
-	// Create an HTTP client using the client creator from the provider
-	client := r.clientCreator(r.endpoint, r.token)
-
 	// Get the app slug from state
-	appSlug := data.AppSlug
+	appSlug := data.AppSlug.ValueString()
 	if appSlug == "" {
 		tflog.Warn(ctx, "AppSlug is empty, skipping Read")
-		resp.State.Set(ctx, &data)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 		return
 	}
 
-	// Construct the URL to get the app details
-	completeURL := fmt.Sprintf("%s/v0.1/apps/%s", r.endpoint, appSlug)
+	// A freshly imported resource only knows its slug
+	imported := data.StackID.IsNull()
 
-	tflog.Debug(ctx, "MODULEDEBUG: Fetching app details for Finish resource", map[string]interface{}{"url": completeURL})
-
-	// Create an HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "GET", completeURL, nil)
-	if err != nil {
-		tflog.Error(ctx, "Error creating HTTP request", map[string]interface{}{"error": err.Error()})
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create request: %s", err))
+	app, err := r.client.GetApp(ctx, appSlug)
+	// If the app was deleted (404), remove it from state
+	if bitrise.IsNotFound(err) {
+		tflog.Info(ctx, "App not found, removing Finish resource from state", map[string]interface{}{"app_slug": appSlug})
+		resp.State.RemoveResource(ctx)
 		return
 	}
-
-	// Send the HTTP request
-	httpResp, err := client.Do(httpReq)
 	if err != nil {
-		tflog.Error(ctx, "Error sending HTTP request", map[string]interface{}{"error": err.Error()})
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read app: %s", err))
+		tflog.Error(ctx, "Request did not succeed", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Unable to read app: %s", err))
 		return
 	}
-	defer httpResp.Body.Close()
 
-	// If the app was deleted (404), remove it from state
-	if httpResp.StatusCode == http.StatusNotFound {
-		tflog.Info(ctx, "App not found, removing Finish resource from state", map[string]interface{}{"app_slug": appSlug})
-		resp.State.RemoveResource(ctx)
-		return
+	if app.ProjectType != "" {
+		data.ProjectType = types.StringValue(app.ProjectType)
+	}
+	if app.Owner.AccountType == "organization" && app.Owner.Slug != "" {
+		data.OrganizationSlug = types.StringValue(app.Owner.Slug)
 	}
 
-	if httpResp.StatusCode != http.StatusOK {
-		responseBody, _ := io.ReadAll(httpResp.Body)
-		tflog.Error(ctx, "Request did not succeed", map[string]interface{}{
-			"status": httpResp.Status,
-			"body":   string(responseBody),
-		})
-		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Unable to read app, got status: %s", httpResp.Status))
+	// The stack and the envs set up by /finish end up in the bitrise.yml
+	yml, err := r.client.GetBitriseYML(ctx, appSlug)
+	if err != nil {
+		tflog.Error(ctx, "Request did not succeed", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Unable to read bitrise.yml: %s", err))
 		return
 	}
 
-	tflog.Debug(ctx, "MODULEDEBUG: App still exists, keeping Finish resource in state")
+	config, err := bitrise.ParseAppConfig(yml)
+	if err != nil {
+		resp.Diagnostics.AddWarning("Unable to Detect Drift",
+			fmt.Sprintf("The stack and envs of app %s could not be read from its bitrise.yml, keeping the values from state: %s", appSlug, err))
+	} else {
+		if config.Stack != "" {
+			data.StackID = types.StringValue(config.Stack)
+		}
+		// Only the envs passed to /finish are tracked, as the bitrise.yml may
+		// define others; an imported resource adopts all of them
+		if imported && len(config.Envs) > 0 {
+			data.Envs = config.Envs
+		} else if data.Envs != nil {
+			envs := make(map[string]string, len(data.Envs))
+			for key := range data.Envs {
+				if value, ok := config.Envs[key]; ok {
+					envs[key] = value
+				}
+			}
+			data.Envs = envs
+		}
+	}
+
+	tflog.Debug(ctx, "MODULEDEBUG: App still exists, refreshed Finish resource from API")
 
 	// Save updated data into Terraform state
-	resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *AppFinishResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data AppFinishResourceModel
+	ctx = r.client.LogContext(ctx)
+
+	var data, state AppFinishResourceModel
 
 	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 
 	if resp.Diagnostics.HasError() {
 		return
@@ -280,81 +316,45 @@ func (r *AppFinishResource) Update(ctx context.Context, req resource.UpdateReque
 
 	tflog.Debug(ctx, "MODULEDEBUG: Starting AppFinishResource Update")
 
-	// Create an HTTP client using the client creator from the provider
-	client := r.clientCreator(r.endpoint, r.token)
-
-	// Construct the URL for updating the request
-	appSlug := data.AppSlug
-	completeURL := fmt.Sprintf("%s/v0.1/apps/%s/finish", r.endpoint, appSlug)
-
-	// Construct the payload data using the provided variables
-	payload := PayloadFinish{
-		ProjectType:      data.ProjectType,
-		StackID:          data.StackID,
-		Config:           data.Config,
-		Mode:             data.Mode,
-		Envs:             data.Envs,
-		OrganizationSlug: data.OrganizationSlug,
-	}
-
-	// Marshal the payload struct into a JSON string
-	payloadJSON, err := json.Marshal(payload)
-	if err != nil {
-		tflog.Error(ctx, "Error marshaling JSON payload", map[string]interface{}{"error": err.Error()})
-		resp.Diagnostics.AddError("JSON Error", fmt.Sprintf("Unable to marshal payload: %s", err))
+	// config and mode of an imported app come from the import ID and cannot
+	// be read back; when nothing else changed, the live app must not be
+	// finished again just to store the configured values
+	imported, diags := req.Private.GetKey(ctx, appFinishImportedKey)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, appFinishImportedKey, nil)...)
+	if len(imported) > 0 && finishSettingsEqual(data, state) {
+		tflog.Info(ctx, "Storing config and mode of imported app without finishing it again")
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 		return
 	}
 
-	tflog.Debug(ctx, "MODULEDEBUG: Update request payload", map[string]interface{}{"payload_json": string(payloadJSON)})
-
-	// Create an HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", completeURL, strings.NewReader(string(payloadJSON)))
-	if err != nil {
-		tflog.Error(ctx, "Error creating HTTP request", map[string]interface{}{"error": err.Error()})
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create request: %s", err))
+	updateTimeout, diags := data.Timeouts.Update(ctx, defaultAppFinishTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
 
-	// Dump the HTTP request details
-	dump, _ := httputil.DumpRequest(httpReq, true)
-	tflog.Debug(ctx, "MODULEDEBUG: HTTP Request", map[string]interface{}{"request": string(dump)})
-
-	// Send the HTTP request
-	httpResp, err := client.Do(httpReq)
-	if err != nil {
-		tflog.Error(ctx, "Error sending HTTP request", map[string]interface{}{"error": err.Error()})
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update app finish: %s", err))
+	if err := r.client.FinishApp(ctx, data.AppSlug.ValueString(), finishParams(data)); err != nil {
+		tflog.Error(ctx, "Request did not succeed", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Request Error", fmt.Sprintf("Unable to update app finish: %s", err))
 		return
 	}
-	defer httpResp.Body.Close()
 
-	// Read the response body
-	responseBody, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		tflog.Error(ctx, "Error reading response body", map[string]interface{}{"error": err.Error()})
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read response: %s", err))
-		return
-	}
-
-	tflog.Debug(ctx, "MODULEDEBUG: Response Body", map[string]interface{}{"body": string(responseBody)})
-
-	// Check response status
-	if httpResp.StatusCode != http.StatusOK {
-		tflog.Error(ctx, "Request did not succeed", map[string]interface{}{
-			"status": httpResp.Status,
-			"body":   string(responseBody),
-		})
-		resp.Diagnostics.AddError("API Request Error", fmt.Sprintf("Request did not succeed: %s - %s", httpResp.Status, string(responseBody)))
+	if !waitForActiveApp(ctx, r.client, data.AppSlug.ValueString(), &resp.Diagnostics) {
 		return
 	}
 
 	tflog.Info(ctx, "App finish configuration updated successfully")
 
 	// Update resource state with populated data
-	resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *AppFinishResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.client.LogContext(ctx)
+
 	var data AppFinishResourceModel
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -368,6 +368,56 @@ func (r *AppFinishResource) Delete(ctx context.Context, req resource.DeleteReque
 	resp.State.RemoveResource(ctx)
 }
 
+// ImportState expects "app_slug/config/mode". The config template and mode
+// are only used by /finish and cannot be read back, every other attribute is
+// populated by Read. Without them, the first apply would finish the live app
+// again.
 func (r *AppFinishResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("app_slug"), req, resp)
+	parts := strings.Split(req.ID, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("Expected import ID in format 'app_slug/config/mode', got: %s", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("app_slug"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("config"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("mode"), parts[2])...)
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, appFinishImportedKey, []byte("true"))...)
+}
+
+// finishSettingsEqual reports whether plan and state agree on every setting
+// that can be read back from the app, so that only config and mode differ.
+func finishSettingsEqual(plan, state AppFinishResourceModel) bool {
+	return plan.AppSlug.Equal(state.AppSlug) &&
+		plan.ProjectType.Equal(state.ProjectType) &&
+		plan.StackID.Equal(state.StackID) &&
+		plan.OrganizationSlug.Equal(state.OrganizationSlug) &&
+		maps.Equal(plan.Envs, state.Envs)
+}
+
+// waitForActiveApp polls the app until its setup is reported as finished, so
+// that resources uploading its bitrise.yml or secrets do not race the setup.
+func waitForActiveApp(ctx context.Context, client *bitrise.Client, appSlug string, diags *diag.Diagnostics) bool {
+	if _, err := client.WaitForApp(ctx, appSlug, (*bitrise.App).IsActive); err != nil {
+		tflog.Error(ctx, "App did not become active", map[string]interface{}{"error": err.Error()})
+		diags.AddError("App Not Ready", fmt.Sprintf("The setup of app %s was submitted but the app did not become active: %s. Increase the timeouts of the resource if the app needs more time.", appSlug, err))
+		return false
+	}
+
+	return true
+}
+
+// finishParams builds the /finish payload from the resource model.
+func finishParams(data AppFinishResourceModel) bitrise.FinishAppParams {
+	return bitrise.FinishAppParams{
+		ProjectType:      data.ProjectType.ValueString(),
+		StackID:          data.StackID.ValueString(),
+		Config:           data.Config.ValueString(),
+		Mode:             data.Mode.ValueString(),
+		Envs:             data.Envs,
+		OrganizationSlug: data.OrganizationSlug.ValueString(),
+	}
 }
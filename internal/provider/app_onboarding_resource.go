@@ -0,0 +1,636 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	"terraform-provider-bitrise/internal/bitrise"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ resource.Resource = &AppOnboardingResource{}
+var _ resource.ResourceWithConfigValidators = &AppOnboardingResource{}
+var _ resource.ResourceWithValidateConfig = &AppOnboardingResource{}
+var _ resource.ResourceWithModifyPlan = &AppOnboardingResource{}
+
+// Onboarding steps, in the order they run.
+const (
+	onboardingStepRegister = "register"
+	onboardingStepSSHKey   = "ssh_key"
+	onboardingStepFinish   = "finish"
+	onboardingStepWebhook  = "webhook"
+)
+
+// onboardingProgressKey is the private state key holding the completed steps.
+const onboardingProgressKey = "onboarding_progress"
+
+type AppOnboardingResource struct {
+	client *bitrise.Client
+}
+
+func NewAppOnboardingResource() resource.Resource {
+	return &AppOnboardingResource{}
+}
+
+type AppOnboardingResourceModel struct {
+	ID               types.String              `tfsdk:"id"`
+	AppSlug          types.String              `tfsdk:"app_slug"`
+	Repo             types.String              `tfsdk:"repo"`
+	RepoURL          types.String              `tfsdk:"repo_url"`
+	Type             types.String              `tfsdk:"type"`
+	GitOwner         types.String              `tfsdk:"git_owner"`
+	GitRepoSlug      types.String              `tfsdk:"git_repo_slug"`
+	OrganizationSlug types.String              `tfsdk:"organization_slug"`
+	IsPublic         types.Bool                `tfsdk:"is_public"`
+	SSHKey           *AppOnboardingSSHKeyModel `tfsdk:"ssh_key"`
+	Finish           *AppOnboardingFinishModel `tfsdk:"finish"`
+	RegisterWebhook  types.Bool                `tfsdk:"register_webhook"`
+	CompletedSteps   types.List                `tfsdk:"completed_steps"`
+}
+
+type AppOnboardingSSHKeyModel struct {
+	AuthSSHPrivateKey                types.String `tfsdk:"auth_ssh_private_key"`
+	AuthSSHPublicKey                 types.String `tfsdk:"auth_ssh_public_key"`
+	IsRegisterKeyIntoProviderService types.Bool   `tfsdk:"is_register_key_into_provider_service"`
+}
+
+type AppOnboardingFinishModel struct {
+	ProjectType types.String      `tfsdk:"project_type"`
+	StackID     types.String      `tfsdk:"stack_id"`
+	Config      types.String      `tfsdk:"config"`
+	Mode        types.String      `tfsdk:"mode"`
+	Envs        map[string]string `tfsdk:"envs"`
+}
+
+// onboardingProgress is persisted in private state after every step, so that
+// a retried apply continues from the step that failed.
+type onboardingProgress struct {
+	Completed []string `json:"completed"`
+}
+
+func (p *onboardingProgress) has(step string) bool {
+	return slices.Contains(p.Completed, step)
+}
+
+func (p *onboardingProgress) add(step string) {
+	if !p.has(step) {
+		p.Completed = append(p.Completed, step)
+	}
+}
+
+func (p *onboardingProgress) remove(step string) {
+	p.Completed = slices.DeleteFunc(p.Completed, func(s string) bool { return s == step })
+}
+
+// privateState is implemented by the private state of the framework requests
+// and responses.
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+func loadOnboardingProgress(ctx context.Context, private privateState, diags *diag.Diagnostics) onboardingProgress {
+	var progress onboardingProgress
+
+	raw, d := private.GetKey(ctx, onboardingProgressKey)
+	diags.Append(d...)
+	if len(raw) == 0 {
+		return progress
+	}
+
+	if err := json.Unmarshal(raw, &progress); err != nil {
+		diags.AddError("Invalid Private State", fmt.Sprintf("Unable to parse the onboarding progress: %s", err))
+	}
+
+	return progress
+}
+
+func saveOnboardingProgress(ctx context.Context, private privateState, progress onboardingProgress, diags *diag.Diagnostics) {
+	raw, err := json.Marshal(progress)
+	if err != nil {
+		diags.AddError("Invalid Private State", fmt.Sprintf("Unable to encode the onboarding progress: %s", err))
+		return
+	}
+
+	diags.Append(private.SetKey(ctx, onboardingProgressKey, raw)...)
+}
+
+// onboardingSteps returns the steps the configuration asks for, in order.
+func onboardingSteps(data *AppOnboardingResourceModel) []string {
+	steps := []string{onboardingStepRegister}
+	if data.SSHKey != nil {
+		steps = append(steps, onboardingStepSSHKey)
+	}
+	steps = append(steps, onboardingStepFinish)
+	if data.RegisterWebhook.ValueBool() {
+		steps = append(steps, onboardingStepWebhook)
+	}
+	return steps
+}
+
+func (r *AppOnboardingResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_app_onboarding"
+}
+
+func (r *AppOnboardingResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Onboards a repository as a Bitrise app in one operation: registers the app, registers its SSH key, finishes the setup and optionally registers the webhook. Progress is tracked per step, so a retried apply continues from the step that failed.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Onboarding identifier (app_slug)",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"app_slug": schema.StringAttribute{
+				MarkdownDescription: "Slug of the registered app",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"repo": schema.StringAttribute{
+				MarkdownDescription: "Git provider of the repository: `github`, `gitlab`, `bitbucket` or `other`. Changing it forces a new app.",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(appRepoProviders...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"repo_url": schema.StringAttribute{
+				MarkdownDescription: "URL of the repository. Changing it forces a new app.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "Type of the repository, `git`. Default: `git`",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("git"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("git"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"git_owner": schema.StringAttribute{
+				MarkdownDescription: "Owner of the repository on the git provider. Changing it forces a new app.",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"git_repo_slug": schema.StringAttribute{
+				MarkdownDescription: "Slug of the repository on the git provider. Changing it forces a new app.",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"organization_slug": schema.StringAttribute{
+				MarkdownDescription: "Slug of the organization owning the app. Changing it forces a new app.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"is_public": schema.BoolAttribute{
+				MarkdownDescription: "Whether the app is public. Updated in place. Default: `false`",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"ssh_key": schema.SingleNestedAttribute{
+				MarkdownDescription: "SSH keypair used to clone the repository. Changing it registers the new key.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"auth_ssh_private_key": schema.StringAttribute{
+						MarkdownDescription: "Unencrypted private SSH key, in PEM or OpenSSH format",
+						Required:            true,
+						Sensitive:           true,
+					},
+					"auth_ssh_public_key": schema.StringAttribute{
+						MarkdownDescription: "Public SSH key, in authorized_keys format",
+						Required:            true,
+					},
+					"is_register_key_into_provider_service": schema.BoolAttribute{
+						MarkdownDescription: "Whether to register the public key into the provider service. Default: `false`",
+						Optional:            true,
+						Computed:            true,
+						Default:             booldefault.StaticBool(false),
+					},
+				},
+			},
+			"finish": schema.SingleNestedAttribute{
+				MarkdownDescription: "Settings of the finish step. Changing them runs the finish step again.",
+				Required:            true,
+				Attributes: map[string]schema.Attribute{
+					"project_type": schema.StringAttribute{
+						MarkdownDescription: "The type of the project",
+						Required:            true,
+					},
+					"stack_id": schema.StringAttribute{
+						MarkdownDescription: "The ID of the stack on which the build will run",
+						Required:            true,
+					},
+					"config": schema.StringAttribute{
+						MarkdownDescription: "The configuration for the app",
+						Required:            true,
+					},
+					"mode": schema.StringAttribute{
+						MarkdownDescription: "The mode of the app (e.g., manual)",
+						Required:            true,
+					},
+					"envs": schema.MapAttribute{
+						MarkdownDescription: "App level environment variables",
+						ElementType:         types.StringType,
+						Optional:            true,
+					},
+				},
+			},
+			"register_webhook": schema.BoolAttribute{
+				MarkdownDescription: "Whether to register the Bitrise webhook on the repository as the last step. Default: `false`",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"completed_steps": schema.ListAttribute{
+				MarkdownDescription: "Onboarding steps completed so far, out of `register`, `ssh_key`, `finish` and `webhook`",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+		},
+	}
+}
+
+func (r *AppOnboardingResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.RequiredTogether(
+			path.MatchRoot("git_owner"),
+			path.MatchRoot("git_repo_slug"),
+		),
+	}
+}
+
+func (r *AppOnboardingResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var sshKey *AppOnboardingSSHKeyModel
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("ssh_key"), &sshKey)...)
+
+	if resp.Diagnostics.HasError() || sshKey == nil {
+		return
+	}
+
+	// Keys coming from other resources are only known at apply time
+	if sshKey.AuthSSHPrivateKey.IsUnknown() || sshKey.AuthSSHPublicKey.IsUnknown() {
+		return
+	}
+
+	if _, err := parseSSHKeypair(sshKey.AuthSSHPrivateKey.ValueString(), sshKey.AuthSSHPublicKey.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("ssh_key").AtName("auth_ssh_private_key"), "Invalid SSH Keypair", err.Error())
+	}
+}
+
+// ModifyPlan validates the stack and plans an update whenever a step is
+// still pending, either because it failed during the last apply or because
+// its settings changed.
+func (r *AppOnboardingResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on destroy or before the provider is configured
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var plan AppOnboardingResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state *AppOnboardingResourceModel
+	if !req.State.Raw.IsNull() {
+		state = &AppOnboardingResourceModel{}
+		resp.Diagnostics.Append(req.State.Get(ctx, state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if plan.Finish != nil && !plan.Finish.StackID.IsUnknown() &&
+		(state == nil || state.Finish == nil || !state.Finish.StackID.Equal(plan.Finish.StackID)) {
+		validateStackID(ctx, r.client, plan.Finish.StackID.ValueString(), path.Root("finish").AtName("stack_id"), &resp.Diagnostics)
+	}
+
+	if state == nil {
+		return
+	}
+
+	progress := loadOnboardingProgress(ctx, req.Private, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	invalidateChangedSteps(&progress, &plan, state)
+
+	pending := false
+	for _, step := range onboardingSteps(&plan) {
+		if !progress.has(step) {
+			pending = true
+		}
+	}
+
+	if pending {
+		plan.CompletedSteps = types.ListUnknown(types.StringType)
+	} else {
+		plan.CompletedSteps = state.CompletedSteps
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *AppOnboardingResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		tflog.Debug(ctx, "MODULEDEBUG: Provider data is missing")
+		return
+	}
+	client, ok := req.ProviderData.(*bitrise.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *bitrise.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+	tflog.Debug(ctx, "MODULEDEBUG: Provider configuration successful")
+}
+
+func (r *AppOnboardingResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.LogContext(ctx)
+
+	var data AppOnboardingResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "MODULEDEBUG: Starting AppOnboardingResource Create")
+
+	var progress onboardingProgress
+	completed := r.onboard(ctx, &data, &progress, &resp.Diagnostics)
+
+	// Nothing exists in Bitrise if the registration itself failed
+	if !progress.has(onboardingStepRegister) {
+		return
+	}
+
+	r.saveState(ctx, &data, progress, &resp.State, resp.Private, &resp.Diagnostics)
+
+	if !completed {
+		resp.Diagnostics.AddError(
+			"App Partially Onboarded",
+			fmt.Sprintf("The app %s was registered in Bitrise and is saved as tainted with its progress. "+
+				"Run `terraform untaint` on this resource to continue from the failed step on the next apply, "+
+				"otherwise the next apply deletes the app and onboards it again.", data.AppSlug.ValueString()),
+		)
+		return
+	}
+
+	tflog.Info(ctx, "App onboarded successfully", map[string]interface{}{"app_slug": data.AppSlug.ValueString()})
+}
+
+func (r *AppOnboardingResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.LogContext(ctx)
+
+	var data AppOnboardingResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "MODULEDEBUG: Starting AppOnboardingResource Read")
+
+	appSlug := data.AppSlug.ValueString()
+	app, err := r.client.GetApp(ctx, appSlug)
+	// If the app was deleted (404), remove it from state
+	if bitrise.IsNotFound(err) {
+		tflog.Info(ctx, "App not found, removing onboarding from state", map[string]interface{}{"app_slug": appSlug})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		tflog.Error(ctx, "Request did not succeed", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Unable to read app: %s", err))
+		return
+	}
+
+	data.IsPublic = types.BoolValue(app.IsPublic)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AppOnboardingResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.client.LogContext(ctx)
+
+	var data, state AppOnboardingResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "MODULEDEBUG: Starting AppOnboardingResource Update")
+
+	progress := loadOnboardingProgress(ctx, req.Private, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	invalidateChangedSteps(&progress, &data, &state)
+
+	if !data.IsPublic.Equal(state.IsPublic) && progress.has(onboardingStepRegister) {
+		isPublic := data.IsPublic.ValueBool()
+		if err := r.client.PatchApp(ctx, state.AppSlug.ValueString(), bitrise.PatchAppParams{IsPublic: &isPublic}); err != nil {
+			tflog.Error(ctx, "Update request did not succeed", map[string]interface{}{"error": err.Error()})
+			resp.Diagnostics.AddError("API Request Error", fmt.Sprintf("Unable to update app: %s", err))
+			return
+		}
+	}
+
+	// Failed steps are recorded in private state and retried by the next
+	// apply; unlike a failed create, a failed update does not taint
+	r.onboard(ctx, &data, &progress, &resp.Diagnostics)
+	r.saveState(ctx, &data, progress, &resp.State, resp.Private, &resp.Diagnostics)
+}
+
+func (r *AppOnboardingResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.client.LogContext(ctx)
+
+	var data AppOnboardingResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "MODULEDEBUG: Starting AppOnboardingResource Delete")
+
+	appSlug := data.AppSlug.ValueString()
+	err := r.client.DeleteApp(ctx, appSlug)
+	if bitrise.IsNotFound(err) {
+		tflog.Info(ctx, "App already deleted", map[string]interface{}{"app_slug": appSlug})
+		err = nil
+	}
+	if err != nil {
+		tflog.Error(ctx, "Delete request did not succeed", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Unable to delete app: %s", err))
+		return
+	}
+
+	resp.State.RemoveResource(ctx)
+}
+
+// onboard runs the pending steps in order and records each completed step in
+// progress. It stops at the first failing step and reports whether every
+// step completed.
+func (r *AppOnboardingResource) onboard(ctx context.Context, data *AppOnboardingResourceModel, progress *onboardingProgress, diags *diag.Diagnostics) bool {
+	for _, step := range onboardingSteps(data) {
+		if progress.has(step) {
+			continue
+		}
+
+		tflog.Debug(ctx, "MODULEDEBUG: Running onboarding step", map[string]interface{}{"step": step})
+
+		if err := r.runStep(ctx, step, data); err != nil {
+			tflog.Error(ctx, "Onboarding step did not succeed", map[string]interface{}{"step": step, "error": err.Error()})
+			diags.AddError("API Request Error", fmt.Sprintf("Unable to complete the %s step of the onboarding: %s", step, err))
+			return false
+		}
+
+		progress.add(step)
+	}
+
+	return true
+}
+
+func (r *AppOnboardingResource) runStep(ctx context.Context, step string, data *AppOnboardingResourceModel) error {
+	appSlug := data.AppSlug.ValueString()
+
+	switch step {
+	case onboardingStepRegister:
+		registered, err := r.client.RegisterApp(ctx, bitrise.RegisterAppParams{
+			Provider:         data.Repo.ValueString(),
+			IsPublic:         data.IsPublic.ValueBool(),
+			OrganizationSlug: data.OrganizationSlug.ValueString(),
+			RepoURL:          data.RepoURL.ValueString(),
+			Type:             data.Type.ValueString(),
+			GitRepoSlug:      data.GitRepoSlug.ValueString(),
+			GitOwner:         data.GitOwner.ValueString(),
+		})
+		if err != nil {
+			return err
+		}
+		data.AppSlug = types.StringValue(registered.Slug)
+		data.ID = types.StringValue(registered.Slug)
+		return nil
+	case onboardingStepSSHKey:
+		if _, err := parseSSHKeypair(data.SSHKey.AuthSSHPrivateKey.ValueString(), data.SSHKey.AuthSSHPublicKey.ValueString()); err != nil {
+			return err
+		}
+		return r.client.RegisterSSHKey(ctx, appSlug, bitrise.RegisterSSHKeyParams{
+			AuthSSHPrivateKey:                data.SSHKey.AuthSSHPrivateKey.ValueString(),
+			AuthSSHPublicKey:                 data.SSHKey.AuthSSHPublicKey.ValueString(),
+			IsRegisterKeyIntoProviderService: data.SSHKey.IsRegisterKeyIntoProviderService.ValueBool(),
+		})
+	case onboardingStepFinish:
+		return r.client.FinishApp(ctx, appSlug, bitrise.FinishAppParams{
+			ProjectType:      data.Finish.ProjectType.ValueString(),
+			StackID:          data.Finish.StackID.ValueString(),
+			Config:           data.Finish.Config.ValueString(),
+			Mode:             data.Finish.Mode.ValueString(),
+			Envs:             data.Finish.Envs,
+			OrganizationSlug: data.OrganizationSlug.ValueString(),
+		})
+	case onboardingStepWebhook:
+		return r.client.RegisterWebhook(ctx, appSlug)
+	}
+
+	return fmt.Errorf("unknown onboarding step %q", step)
+}
+
+// saveState stores data with the completed steps and persists the progress
+// in private state.
+func (r *AppOnboardingResource) saveState(ctx context.Context, data *AppOnboardingResourceModel, progress onboardingProgress, state stateSetter, private privateState, diags *diag.Diagnostics) {
+	completed, d := types.ListValueFrom(ctx, types.StringType, progress.Completed)
+	diags.Append(d...)
+	data.CompletedSteps = completed
+
+	diags.Append(state.Set(ctx, data)...)
+	saveOnboardingProgress(ctx, private, progress, diags)
+}
+
+// stateSetter is implemented by tfsdk.State.
+type stateSetter interface {
+	Set(ctx context.Context, val interface{}) diag.Diagnostics
+}
+
+// invalidateChangedSteps drops the steps whose settings changed since they
+// ran, so that they run again.
+func invalidateChangedSteps(progress *onboardingProgress, plan, state *AppOnboardingResourceModel) {
+	if sshKeyChanged(plan.SSHKey, state.SSHKey) {
+		progress.remove(onboardingStepSSHKey)
+	}
+	if finishChanged(plan.Finish, state.Finish) {
+		progress.remove(onboardingStepFinish)
+	}
+}
+
+func sshKeyChanged(plan, state *AppOnboardingSSHKeyModel) bool {
+	if plan == nil || state == nil {
+		return plan != state
+	}
+
+	return !plan.AuthSSHPrivateKey.Equal(state.AuthSSHPrivateKey) ||
+		!plan.AuthSSHPublicKey.Equal(state.AuthSSHPublicKey) ||
+		!plan.IsRegisterKeyIntoProviderService.Equal(state.IsRegisterKeyIntoProviderService)
+}
+
+func finishChanged(plan, state *AppOnboardingFinishModel) bool {
+	if plan == nil || state == nil {
+		return plan != state
+	}
+
+	return !plan.ProjectType.Equal(state.ProjectType) ||
+		!plan.StackID.Equal(state.StackID) ||
+		!plan.Config.Equal(state.Config) ||
+		!plan.Mode.Equal(state.Mode) ||
+		!maps.Equal(plan.Envs, state.Envs)
+}
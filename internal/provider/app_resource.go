@@ -2,54 +2,100 @@ package provider
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
-	"net/http/httputil"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
+	"terraform-provider-bitrise/internal/bitrise"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
-type AppResource struct {
-	clientCreator func(endpoint, token string) *http.Client
-	endpoint      string
-	token         string
+var _ resource.Resource = &AppResource{}
+var _ resource.ResourceWithImportState = &AppResource{}
+var _ resource.ResourceWithConfigValidators = &AppResource{}
+var _ resource.ResourceWithValidateConfig = &AppResource{}
+
+// Git providers accepted by POST /v0.1/apps/register.
+var appRepoProviders = []string{"github", "gitlab", "bitbucket", "other"}
+
+// What destroying a bitrise_app does to the app in Bitrise.
+const (
+	appOnDestroyDelete  = "delete"
+	appOnDestroyDisable = "disable"
+	appOnDestroyAbandon = "abandon"
+)
+
+// appTypeGit is the only repository type Bitrise supports.
+const appTypeGit = "git"
+
+// appAvatarURLKey is the private state key holding the avatar URL last read
+// from the API, used to detect an avatar changed outside of Terraform.
+const appAvatarURLKey = "avatar_url"
+
+// defaultAppCreateTimeout bounds the registration and the wait for the app.
+const defaultAppCreateTimeout = 10 * time.Minute
+
+// requiresReplaceUnlessRepoURLChanges replaces the app when a repository
+// attribute changes on its own. When repo_url changes as well, the PATCH of
+// repository_url moves the app to the new repository in place.
+func requiresReplaceUnlessRepoURLChanges() planmodifier.String {
+	return stringplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+			var planURL, stateURL types.String
+			resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("repo_url"), &planURL)...)
+			resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("repo_url"), &stateURL)...)
+			resp.RequiresReplace = planURL.Equal(stateURL)
+		},
+		"Requires replacement unless repo_url changes as well",
+		"Requires replacement unless `repo_url` changes as well",
+	)
 }
 
-func NewAppResource(clientCreator func(endpoint, token string) *http.Client, endpoint, token string) *AppResource {
-	return &AppResource{
-		clientCreator: clientCreator,
-		endpoint:      endpoint,
-		token:         token,
-	}
+type AppResource struct {
+	client *bitrise.Client
 }
 
-// Define a struct that matches the structure of the JSON response
-type CreateResponse struct {
-	Status     string `json:"status"`
-	Slug       string `json:"slug"`
-	ProviderID string `json:"provider_id"`
+func NewAppResource() resource.Resource {
+	return &AppResource{}
 }
 
 type AppResourceModel struct {
-	ConfigurableAttribute types.String `tfsdk:"configurable_attribute"`
-	Id                    types.String `tfsdk:"id"`
-	Repo                  types.String `tfsdk:"repo"`
-	IsPublic              types.Bool   `tfsdk:"is_public"`
-	OrganizationSlug      types.String `tfsdk:"organization_slug"`
-	RepoURL               types.String `tfsdk:"repo_url"`
-	Type                  types.String `tfsdk:"type"`
-	GitRepoSlug           types.String `tfsdk:"git_repo_slug"`
-	GitOwner              types.String `tfsdk:"git_owner"`
-	AppSlug               types.String `tfsdk:"app_slug"`
+	ConfigurableAttribute types.String   `tfsdk:"configurable_attribute"`
+	Id                    types.String   `tfsdk:"id"`
+	Repo                  types.String   `tfsdk:"repo"`
+	IsPublic              types.Bool     `tfsdk:"is_public"`
+	OrganizationSlug      types.String   `tfsdk:"organization_slug"`
+	RepoURL               types.String   `tfsdk:"repo_url"`
+	Type                  types.String   `tfsdk:"type"`
+	GitRepoSlug           types.String   `tfsdk:"git_repo_slug"`
+	GitOwner              types.String   `tfsdk:"git_owner"`
+	AppSlug               types.String   `tfsdk:"app_slug"`
+	DeletionProtection    types.Bool     `tfsdk:"deletion_protection"`
+	OnDestroy             types.String   `tfsdk:"on_destroy"`
+	Title                 types.String   `tfsdk:"title"`
+	DefaultBranch         types.String   `tfsdk:"default_branch"`
+	AvatarPath            types.String   `tfsdk:"avatar_path"`
+	AvatarBase64          types.String   `tfsdk:"avatar_base64"`
+	Timeouts              timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *AppResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -72,61 +118,211 @@ func (r *AppResource) Schema(ctx context.Context, req resource.SchemaRequest, re
 				},
 			},
 			"repo": schema.StringAttribute{
-				MarkdownDescription: "Repo",
+				MarkdownDescription: "Git provider of the repository: `github`, `gitlab`, `bitbucket` or `other`. Changing it forces a new app.",
 				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(appRepoProviders...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"is_public": schema.BoolAttribute{
-				MarkdownDescription: "Is Public",
+				MarkdownDescription: "Whether the app is public. Updated in place. Default: `false`",
 				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
 			},
 			"organization_slug": schema.StringAttribute{
-				MarkdownDescription: "Organization Slug",
+				MarkdownDescription: "Slug of the organization owning the app. Changing it forces a new app.",
 				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"repo_url": schema.StringAttribute{
-				MarkdownDescription: "Repo URL",
+				MarkdownDescription: "URL of the repository. Updated in place.",
 				Optional:            true,
 			},
 			"type": schema.StringAttribute{
-				MarkdownDescription: "Type",
+				MarkdownDescription: "Type of the repository, `git`. Changing it forces a new app. Default: `git`",
 				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(appTypeGit),
+				Validators: []validator.String{
+					stringvalidator.OneOf(appTypeGit),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"git_repo_slug": schema.StringAttribute{
-				MarkdownDescription: "Git Repo Slug",
+				MarkdownDescription: "Slug of the repository on the git provider. Changing it forces a new app unless `repo_url` changes as well.",
 				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("repo_url")),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					requiresReplaceUnlessRepoURLChanges(),
+				},
 			},
 			"git_owner": schema.StringAttribute{
-				MarkdownDescription: "Git Owner",
+				MarkdownDescription: "Owner of the repository on the git provider. Changing it forces a new app unless `repo_url` changes as well.",
 				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("repo_url")),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					requiresReplaceUnlessRepoURLChanges(),
+				},
 			},
 			"app_slug": schema.StringAttribute{
 				MarkdownDescription: "App Slug",
 				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"title": schema.StringAttribute{
+				MarkdownDescription: "Title of the app. Read back from the app when not set.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"default_branch": schema.StringAttribute{
+				MarkdownDescription: "Default branch of the repository used by the app. Read back from the app when not set.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
+			"avatar_path": schema.StringAttribute{
+				MarkdownDescription: "Path of a PNG, JPEG or GIF image uploaded as the avatar of the app. Conflicts with `avatar_base64`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("avatar_base64")),
+				},
+			},
+			"avatar_base64": schema.StringAttribute{
+				MarkdownDescription: "Base64 encoded PNG, JPEG or GIF image uploaded as the avatar of the app, e.g. from `filebase64()`. Conflicts with `avatar_path`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("avatar_path")),
+				},
+			},
+			"deletion_protection": schema.BoolAttribute{
+				MarkdownDescription: "Whether destroying or replacing the app fails. It has to be set to `false` and applied before the app can be destroyed. Default: `false`",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"on_destroy": schema.StringAttribute{
+				MarkdownDescription: "What destroying the resource does to the app: `delete` deletes it with its build history, `disable` disables its builds and keeps it, `abandon` only removes it from state. Default: `delete`",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(appOnDestroyDelete),
+				Validators: []validator.String{
+					stringvalidator.OneOf(appOnDestroyDelete, appOnDestroyDisable, appOnDestroyAbandon),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+			}),
 		},
 	}
 }
 
+func (r *AppResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.RequiredTogether(
+			path.MatchRoot("git_owner"),
+			path.MatchRoot("git_repo_slug"),
+		),
+	}
+}
+
+// ValidateConfig rejects git_owner and git_repo_slug values that contradict
+// repo_url. Repositories of the "other" provider can be hosted anywhere, so
+// their URL layout is not checked.
+func (r *AppResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data AppResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.AvatarPath.IsUnknown() && !data.AvatarBase64.IsUnknown() {
+		if _, _, err := appAvatar(&data); err != nil {
+			attr := path.Root("avatar_path")
+			if !data.AvatarBase64.IsNull() {
+				attr = path.Root("avatar_base64")
+			}
+			resp.Diagnostics.AddAttributeError(attr, "Invalid Avatar", err.Error())
+		}
+	}
+
+	for _, value := range []types.String{data.Repo, data.RepoURL, data.GitOwner, data.GitRepoSlug} {
+		if value.IsUnknown() {
+			return
+		}
+	}
+	if data.RepoURL.IsNull() || data.GitOwner.IsNull() || data.GitRepoSlug.IsNull() || data.Repo.ValueString() == "other" {
+		return
+	}
+
+	// host/owner[/subgroups]/repo
+	segments := strings.Split(normalizeRepoURL(data.RepoURL.ValueString()), "/")
+	if len(segments) < 3 {
+		return
+	}
+	owner := strings.Join(segments[1:len(segments)-1], "/")
+	slug := segments[len(segments)-1]
+
+	if !strings.EqualFold(data.GitRepoSlug.ValueString(), slug) {
+		resp.Diagnostics.AddAttributeError(path.Root("git_repo_slug"), "Conflicting Repository Attributes",
+			fmt.Sprintf("git_repo_slug %q conflicts with repo_url %q, which points to repository %q.", data.GitRepoSlug.ValueString(), data.RepoURL.ValueString(), slug))
+	}
+	if !strings.EqualFold(data.GitOwner.ValueString(), owner) && !strings.EqualFold(data.GitOwner.ValueString(), segments[1]) {
+		resp.Diagnostics.AddAttributeError(path.Root("git_owner"), "Conflicting Repository Attributes",
+			fmt.Sprintf("git_owner %q conflicts with repo_url %q, which points to owner %q.", data.GitOwner.ValueString(), data.RepoURL.ValueString(), owner))
+	}
+}
+
 func (r *AppResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		tflog.Debug(ctx, "MODULEDEBUG: Provider data is missing")
 		return
 	}
-	clientCreator, ok := req.ProviderData.(func(endpoint, token string) *http.Client)
+	client, ok := req.ProviderData.(*bitrise.Client)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *bitrise.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	r.clientCreator = clientCreator
+	r.client = client
 	tflog.Debug(ctx, "MODULEDEBUG: Provider configuration successful")
 }
 
 func (r *AppResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.LogContext(ctx)
+
 	// Initialize data to store API response
 	var data AppResourceModel
 
@@ -140,109 +336,77 @@ func (r *AppResource) Create(ctx context.Context, req resource.CreateRequest, re
 
 	tflog.Debug(ctx, "MODULEDEBUG: Starting AppResource Create")
 
-	// Create an HTTP client using the client creator from the provider
-	client := r.clientCreator(r.endpoint, r.token)
-
-	// Construct the URL for creating the request
-	apiPath := "/v0.1/apps/register"
-	completeURL := r.endpoint + apiPath
-	
-	// Retrieve values from Terraform variables using ValueString() to get the actual string value
-	repo := data.Repo.ValueString()
-	isPublic := data.IsPublic.ValueBool()
-	organizationSlug := data.OrganizationSlug.ValueString()
-	repoURL := data.RepoURL.ValueString()
-	typeValue := data.Type.ValueString()
-	gitRepoSlug := data.GitRepoSlug.ValueString()
-	gitOwner := data.GitOwner.ValueString()
-
-	// Construct the payload data using the provided variables
-	payloadData := map[string]interface{}{
-		"provider":          repo,
-		"is_public":         isPublic,
-		"organization_slug": organizationSlug,
-		"repo_url":          repoURL,
-		"type":              typeValue,
-		"git_repo_slug":     gitRepoSlug,
-		"git_owner":         gitOwner,
-	}
-
-	// Marshal the payload to JSON
-	payloadBytes, err := json.Marshal(payloadData)
-	if err != nil {
-		tflog.Error(ctx, "Error marshaling payload", map[string]interface{}{"error": err.Error()})
-		handleRequestError(err, resp)
-		return
-	}
-	payload := string(payloadBytes)
-
-	tflog.Debug(ctx, "MODULEDEBUG: Request payload", map[string]interface{}{"payload": payload})
-
-	// Create an HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", completeURL, strings.NewReader(payload))
-	if err != nil {
-		tflog.Error(ctx, "Error creating HTTP request", map[string]interface{}{"error": err.Error()})
-		handleRequestError(err, resp)
-		return
-	}
-	// Dump the HTTP request details
-	dump, _ := httputil.DumpRequest(httpReq, true)
-	tflog.Debug(ctx, "MODULEDEBUG: HTTP Request", map[string]interface{}{"request": string(dump)})
-
-	// Send the HTTP request
-	httpResp, err := client.Do(httpReq)
-	if err != nil {
-		tflog.Error(ctx, "Error sending HTTP request", map[string]interface{}{"error": err.Error()})
-		handleRequestError(err, resp)
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultAppCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	defer httpResp.Body.Close()
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
 
-	// Read the response body
-	responseBody, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		tflog.Error(ctx, "Error reading response body", map[string]interface{}{"error": err.Error()})
-		handleRequestError(err, resp)
-		return
+	// Construct the payload data using the provided variables
+	params := bitrise.RegisterAppParams{
+		Provider:         data.Repo.ValueString(),
+		IsPublic:         data.IsPublic.ValueBool(),
+		OrganizationSlug: data.OrganizationSlug.ValueString(),
+		RepoURL:          data.RepoURL.ValueString(),
+		Type:             data.Type.ValueString(),
+		GitRepoSlug:      data.GitRepoSlug.ValueString(),
+		GitOwner:         data.GitOwner.ValueString(),
 	}
-	tflog.Debug(ctx, "MODULEDEBUG: Response body", map[string]interface{}{"body": string(responseBody)})
 
-	// Parse the response JSON
-	// Unmarshal the JSON response into the CreateResponse struct
-	var jsonResponse CreateResponse
-	err = json.Unmarshal(responseBody, &jsonResponse)
+	registered, err := r.client.RegisterApp(ctx, params)
 	if err != nil {
-		tflog.Error(ctx, "Error parsing JSON response", map[string]interface{}{"error": err.Error()})
-		handleRequestError(err, resp)
+		tflog.Error(ctx, "Error registering app", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Request Error", fmt.Sprintf("Unable to register app: %s", err))
 		return
 	}
 
 	// Print the captured slug
-	tflog.Debug(ctx, "MODULEDEBUG: Captured app slug", map[string]interface{}{"slug": jsonResponse.Slug})
-	data.AppSlug = types.StringValue(jsonResponse.Slug)
+	tflog.Debug(ctx, "MODULEDEBUG: Captured app slug", map[string]interface{}{"slug": registered.Slug})
+	data.AppSlug = types.StringValue(registered.Slug)
 	// Use the app slug as the ID
-	data.Id = types.StringValue(jsonResponse.Slug)
-
-	// Debugging: Print response status and headers
-	printResponseInfo(httpResp)
-
-	if httpResp.StatusCode != http.StatusOK {
-		tflog.Error(ctx, "Request did not succeed", map[string]interface{}{
-			"status": httpResp.Status,
-			"headers": httpResp.Header,
-		})
-		resp.Diagnostics.AddError("API Request Error", fmt.Sprintf("Request did not succeed: %s", httpResp.Status))
+	data.Id = types.StringValue(registered.Slug)
+
+	// From here on the app exists in Bitrise, failures must keep it in state
+
+	// The title, default branch and avatar cannot be passed to the registration
+	if params, ok := appSettingsPatch(&data, nil); ok {
+		if err := r.client.PatchApp(ctx, registered.Slug, params); err != nil {
+			tflog.Error(ctx, "Error updating registered app", map[string]interface{}{"error": err.Error()})
+			savePartialApp(ctx, &data, resp, fmt.Sprintf("Unable to set the title and default branch of the registered app: %s", err))
+			return
+		}
+	}
+	if !data.AvatarPath.IsNull() || !data.AvatarBase64.IsNull() {
+		if err := r.uploadAvatar(ctx, &data); err != nil {
+			tflog.Error(ctx, "Error uploading app avatar", map[string]interface{}{"error": err.Error()})
+			savePartialApp(ctx, &data, resp, fmt.Sprintf("Unable to upload the avatar of the registered app: %s", err))
+			return
+		}
+	}
+
+	// Dependent resources fail until the registered app can be read back. The
+	// app only reports the active status once bitrise_app_finish ran, which
+	// waits for it, so registration cannot wait for IsActive.
+	app, err := r.client.WaitForApp(ctx, registered.Slug, nil)
+	if err != nil {
+		tflog.Error(ctx, "Error reading registered app", map[string]interface{}{"error": err.Error()})
+		savePartialApp(ctx, &data, resp, fmt.Sprintf("Unable to read registered app: %s", err))
 		return
 	}
+	setAppModel(&data, app)
+	clearUnknownAppAttributes(&data)
 
 	tflog.Info(ctx, "Resource created successfully")
 
 	// Update resource state with populated data
-	resp.State.Set(ctx, &data)
-
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *AppResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.client.LogContext(ctx)
+
 	var data AppResourceModel
 
 	// Retrieve values from Terraform state
@@ -254,43 +418,69 @@ func (r *AppResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 
 	tflog.Debug(ctx, "MODULEDEBUG: Starting AppResource Delete")
 
-	// Create an HTTP client using the client creator from the provider
-	client := r.clientCreator(r.endpoint, r.token)
-
-	// Construct the URL for deleting the request
-	// Get the actual app slug value
 	appSlug := data.AppSlug.ValueString()
-	completeURL := fmt.Sprintf("%s/v0.1/apps/%s", r.endpoint, appSlug)
-
-	tflog.Debug(ctx, "MODULEDEBUG: Delete URL", map[string]interface{}{"url": completeURL})
 
-	// Create an HTTP request with DELETE method
-	httpReq, err := http.NewRequestWithContext(ctx, "DELETE", completeURL, nil)
-	if err != nil {
-		tflog.Error(ctx, "Error creating HTTP request to delete APP", map[string]interface{}{"error": err.Error()})
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create delete request: %s", err))
+	if data.DeletionProtection.ValueBool() {
+		resp.Diagnostics.AddError(
+			"Deletion Protection Enabled",
+			fmt.Sprintf("App %s has deletion_protection enabled and was not destroyed. Set deletion_protection to false and apply before destroying or replacing it.", appSlug),
+		)
 		return
 	}
 
-	// Send the HTTP request
-	httpResp, err := client.Do(httpReq)
-	if err != nil {
-		tflog.Error(ctx, "Error sending HTTP request", map[string]interface{}{"error": err.Error()})
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete app: %s", err))
+	switch data.OnDestroy.ValueString() {
+	case appOnDestroyAbandon:
+		tflog.Info(ctx, "Abandoning app, it is kept in Bitrise", map[string]interface{}{"app_slug": appSlug})
+		resp.Diagnostics.AddWarning("App Abandoned",
+			fmt.Sprintf("App %s was removed from the Terraform state but still exists in Bitrise, as on_destroy is %q.", appSlug, appOnDestroyAbandon))
+		resp.State.RemoveResource(ctx)
+		return
+	case appOnDestroyDisable:
+		isDisabled := true
+		err := r.client.PatchApp(ctx, appSlug, bitrise.PatchAppParams{IsDisabled: &isDisabled})
+		if bitrise.IsNotFound(err) {
+			tflog.Info(ctx, "App already deleted", map[string]interface{}{"app_slug": appSlug})
+			err = nil
+		}
+		if err != nil {
+			tflog.Error(ctx, "Disable request did not succeed", map[string]interface{}{"error": err.Error()})
+			resp.Diagnostics.AddError("API Error", fmt.Sprintf("Unable to disable app: %s", err))
+			return
+		}
+
+		// Read the app back, so that an app which keeps building is never
+		// dropped from state silently
+		app, err := r.client.GetApp(ctx, appSlug)
+		if bitrise.IsNotFound(err) {
+			tflog.Info(ctx, "App already deleted", map[string]interface{}{"app_slug": appSlug})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		if err != nil {
+			tflog.Error(ctx, "Request did not succeed", map[string]interface{}{"error": err.Error()})
+			resp.Diagnostics.AddError("API Error", fmt.Sprintf("Unable to verify that app %s was disabled: %s", appSlug, err))
+			return
+		}
+		if !app.IsDisabled {
+			resp.Diagnostics.AddError("App Not Disabled",
+				fmt.Sprintf("Bitrise accepted the request to disable app %s but still reports it as enabled. The app is kept in the Terraform state, check it in Bitrise and destroy it again.", appSlug))
+			return
+		}
+
+		tflog.Info(ctx, "App disabled, removing from state", map[string]interface{}{"app_slug": appSlug})
+		resp.State.RemoveResource(ctx)
 		return
 	}
-	defer httpResp.Body.Close()
-
-	// Debugging: Print response status and headers
-	printResponseInfo(httpResp)
 
-	if httpResp.StatusCode != http.StatusOK && httpResp.StatusCode != http.StatusNoContent {
-		responseBody, _ := io.ReadAll(httpResp.Body)
-		tflog.Error(ctx, "Delete request did not succeed", map[string]interface{}{
-			"status": httpResp.Status,
-			"body":   string(responseBody),
-		})
-		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Unable to delete app: %s - %s", httpResp.Status, string(responseBody)))
+	err := r.client.DeleteApp(ctx, appSlug)
+	// An app already gone, e.g. a tainted app removed by hand, needs no cleanup
+	if bitrise.IsNotFound(err) {
+		tflog.Info(ctx, "App already deleted", map[string]interface{}{"app_slug": appSlug})
+		err = nil
+	}
+	if err != nil {
+		tflog.Error(ctx, "Delete request did not succeed", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Unable to delete app: %s", err))
 		return
 	}
 
@@ -301,6 +491,8 @@ func (r *AppResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 }
 
 func (r *AppResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.LogContext(ctx)
+
 	var data AppResourceModel
 
 	// Read Terraform prior state data into the model
@@ -312,119 +504,85 @@ func (r *AppResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 
 	tflog.Debug(ctx, "MODULEDEBUG: Starting AppResource Read")
 
-	// Create an HTTP client using the client creator from the provider
-	client := r.clientCreator(r.endpoint, r.token)
-
 	// Get the app slug from state
 	appSlug := data.AppSlug.ValueString()
 	if appSlug == "" {
 		tflog.Warn(ctx, "AppSlug is empty, skipping Read")
-		resp.State.Set(ctx, &data)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 		return
 	}
 
-	// Construct the URL to get the app details
-	completeURL := fmt.Sprintf("%s/v0.1/apps/%s", r.endpoint, appSlug)
-
-	tflog.Debug(ctx, "MODULEDEBUG: Fetching app details", map[string]interface{}{"url": completeURL})
-
-	// Create an HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "GET", completeURL, nil)
-	if err != nil {
-		tflog.Error(ctx, "Error creating HTTP request", map[string]interface{}{"error": err.Error()})
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create request: %s", err))
-		return
-	}
-
-	// Send the HTTP request
-	httpResp, err := client.Do(httpReq)
-	if err != nil {
-		tflog.Error(ctx, "Error sending HTTP request", map[string]interface{}{"error": err.Error()})
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read app: %s", err))
-		return
-	}
-	defer httpResp.Body.Close()
-
+	app, err := r.client.GetApp(ctx, appSlug)
 	// If the app was deleted (404), remove it from state
-	if httpResp.StatusCode == http.StatusNotFound {
+	if bitrise.IsNotFound(err) {
 		tflog.Info(ctx, "App not found, removing from state", map[string]interface{}{"app_slug": appSlug})
 		resp.State.RemoveResource(ctx)
 		return
 	}
-
-	if httpResp.StatusCode != http.StatusOK {
-		responseBody, _ := io.ReadAll(httpResp.Body)
-		tflog.Error(ctx, "Request did not succeed", map[string]interface{}{
-			"status": httpResp.Status,
-			"body":   string(responseBody),
-		})
-		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Unable to read app, got status: %s", httpResp.Status))
-		return
-	}
-
-	// Read the response body
-	responseBody, err := io.ReadAll(httpResp.Body)
 	if err != nil {
-		tflog.Error(ctx, "Error reading response body", map[string]interface{}{"error": err.Error()})
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read response: %s", err))
+		tflog.Error(ctx, "Request did not succeed", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Unable to read app: %s", err))
 		return
 	}
 
-	tflog.Debug(ctx, "MODULEDEBUG: Response body", map[string]interface{}{"body": string(responseBody)})
-
-	// Parse the response JSON to update state with actual values from API
-	type AppDetailsResponse struct {
-		Data struct {
-			Slug      string `json:"slug"`
-			RepoURL   string `json:"repo_url"`
-			IsPublic  bool   `json:"is_public"`
-			Owner     struct {
-				AccountType string `json:"account_type"`
-				Name        string `json:"name"`
-				Slug        string `json:"slug"`
-			} `json:"owner"`
-			RepoSlug string `json:"repo_slug"`
-			Provider string `json:"provider"`
-			RepoOwner string `json:"repo_owner"`
-		} `json:"data"`
+	// An avatar replaced or removed outside of Terraform is uploaded again
+	avatarURL := ""
+	if app.AvatarURL != nil {
+		avatarURL = *app.AvatarURL
 	}
-
-	var apiResponse AppDetailsResponse
-	err = json.Unmarshal(responseBody, &apiResponse)
-	if err != nil {
-		tflog.Error(ctx, "Error parsing JSON response", map[string]interface{}{"error": err.Error()})
-		resp.Diagnostics.AddError("Parse Error", fmt.Sprintf("Unable to parse API response: %s", err))
-		return
+	storedURL, diags := req.Private.GetKey(ctx, appAvatarURLKey)
+	resp.Diagnostics.Append(diags...)
+	var lastURL string
+	if len(storedURL) > 0 {
+		if err := json.Unmarshal(storedURL, &lastURL); err != nil {
+			tflog.Warn(ctx, "Unable to parse stored avatar URL", map[string]interface{}{"error": err.Error()})
+		}
 	}
+	if lastURL != "" && lastURL != avatarURL {
+		tflog.Info(ctx, "App avatar changed outside of Terraform", map[string]interface{}{"app_slug": appSlug})
+		data.AvatarPath = types.StringNull()
+		data.AvatarBase64 = types.StringNull()
+	}
+	setAvatarURL(ctx, resp.Private, avatarURL, &resp.Diagnostics)
 
 	// Update the data model with values from API
-	if apiResponse.Data.RepoURL != "" {
-		data.RepoURL = types.StringValue(apiResponse.Data.RepoURL)
+	setAppModel(&data, app)
+
+	// type is not returned by the API. Imported apps, and apps created before
+	// it had a default, get the default so that it plans no replacement.
+	if data.Type.IsNull() {
+		data.Type = types.StringValue(appTypeGit)
 	}
-	data.IsPublic = types.BoolValue(apiResponse.Data.IsPublic)
-	if apiResponse.Data.RepoOwner != "" {
-		data.GitOwner = types.StringValue(apiResponse.Data.RepoOwner)
+
+	// Imported apps start without the provider-side settings. Left null,
+	// organization_slug would force a replacement on the first plan.
+	if data.OnDestroy.IsNull() {
+		// User-owned apps are configured without organization_slug
+		if data.OrganizationSlug.IsNull() && app.Owner.AccountType == "organization" {
+			data.OrganizationSlug = types.StringValue(app.Owner.Slug)
+		}
 	}
-	if apiResponse.Data.RepoSlug != "" {
-		data.GitRepoSlug = types.StringValue(apiResponse.Data.RepoSlug)
+	if data.DeletionProtection.IsNull() {
+		data.DeletionProtection = types.BoolValue(false)
 	}
-	if apiResponse.Data.Provider != "" {
-		data.Repo = types.StringValue(apiResponse.Data.Provider)
+	if data.OnDestroy.IsNull() {
+		data.OnDestroy = types.StringValue(appOnDestroyDelete)
 	}
-	// Update ID with the app slug
-	data.Id = types.StringValue(apiResponse.Data.Slug)
 
 	tflog.Debug(ctx, "MODULEDEBUG: App details updated from API")
 
 	// Save updated data into Terraform state
-	resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *AppResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data AppResourceModel
+	ctx = r.client.LogContext(ctx)
+
+	var data, state AppResourceModel
 
 	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 
 	if resp.Diagnostics.HasError() {
 		return
@@ -432,92 +590,249 @@ func (r *AppResource) Update(ctx context.Context, req resource.UpdateRequest, re
 
 	tflog.Debug(ctx, "MODULEDEBUG: Starting AppResource Update")
 
-	// Create an HTTP client using the client creator from the provider
-	client := r.clientCreator(r.endpoint, r.token)
-
 	// Get the app slug from state
 	appSlug := data.AppSlug.ValueString()
-	
-	// Construct the URL for updating the request
-	completeURL := fmt.Sprintf("%s/v0.1/apps/%s", r.endpoint, appSlug)
 
-	// Construct the payload data using the provided variables
-	payloadData := map[string]interface{}{
-		"is_public": data.IsPublic.ValueBool(),
+	// deletion_protection and on_destroy only live in the Terraform state
+	settings, settingsChanged := appSettingsPatch(&data, &state)
+	if settingsChanged || !data.IsPublic.Equal(state.IsPublic) || !data.RepoURL.Equal(state.RepoURL) {
+		isPublic := data.IsPublic.ValueBool()
+		params := settings
+		params.IsPublic = &isPublic
+		params.RepositoryURL = data.RepoURL.ValueString()
+
+		if err := r.client.PatchApp(ctx, appSlug, params); err != nil {
+			tflog.Error(ctx, "Update request did not succeed", map[string]interface{}{"error": err.Error()})
+			resp.Diagnostics.AddError("API Request Error", fmt.Sprintf("Unable to update app: %s", err))
+			return
+		}
 	}
-	
-	// Add repo_url if it's not empty
-	repoURL := data.RepoURL.ValueString()
-	if repoURL != "" {
-		payloadData["repository_url"] = repoURL
+
+	avatarChanged := !data.AvatarPath.Equal(state.AvatarPath) || !data.AvatarBase64.Equal(state.AvatarBase64)
+	if avatarChanged && (!data.AvatarPath.IsNull() || !data.AvatarBase64.IsNull()) {
+		if err := r.uploadAvatar(ctx, &data); err != nil {
+			tflog.Error(ctx, "Avatar upload did not succeed", map[string]interface{}{"error": err.Error()})
+			resp.Diagnostics.AddError("API Request Error", fmt.Sprintf("Unable to upload app avatar: %s", err))
+			return
+		}
+		// The next refresh records the URL of the new avatar
+		setAvatarURL(ctx, resp.Private, "", &resp.Diagnostics)
 	}
 
-	// Marshal the payload to JSON
-	payloadBytes, err := json.Marshal(payloadData)
-	if err != nil {
-		tflog.Error(ctx, "Error marshaling payload", map[string]interface{}{"error": err.Error()})
-		resp.Diagnostics.AddError("JSON Error", fmt.Sprintf("Unable to marshal payload: %s", err))
+	tflog.Info(ctx, "App updated successfully")
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// ImportState accepts an app slug, "org:<org_slug>/repo:<repo_url>" or
+// "org:<org_slug>/title:<title>". The latter two are resolved to a slug by
+// searching the apps of the organization. Read then populates the attributes
+// and fills in the inputs the API does not return.
+func (r *AppResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ctx = r.client.LogContext(ctx)
+
+	appSlug := req.ID
+
+	if orgPart, lookup, ok := strings.Cut(req.ID, "/"); ok && strings.HasPrefix(orgPart, "org:") {
+		orgSlug := strings.TrimPrefix(orgPart, "org:")
+		key, value, _ := strings.Cut(lookup, ":")
+		if orgSlug == "" || value == "" || key != "repo" && key != "title" {
+			resp.Diagnostics.AddError(
+				"Invalid Import ID",
+				fmt.Sprintf("Expected import ID in format 'app_slug', 'org:<org_slug>/repo:<repo_url>' or 'org:<org_slug>/title:<title>', got: %s", req.ID),
+			)
+			return
+		}
+
+		app := r.findOrganizationApp(ctx, orgSlug, key, value, &resp.Diagnostics)
+		if app == nil {
+			return
+		}
+		appSlug = app.Slug
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("organization_slug"), orgSlug)...)
+	} else if appSlug == "" || strings.Contains(appSlug, "/") {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("Expected import ID in format 'app_slug', 'org:<org_slug>/repo:<repo_url>' or 'org:<org_slug>/title:<title>', got: %s", req.ID),
+		)
 		return
 	}
-	payload := string(payloadBytes)
 
-	tflog.Debug(ctx, "MODULEDEBUG: Update request payload", map[string]interface{}{"payload": payload})
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), appSlug)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("app_slug"), appSlug)...)
+}
+
+// findOrganizationApp returns the only app of an organization whose
+// repository URL (key "repo") or title (key "title") matches value.
+func (r *AppResource) findOrganizationApp(ctx context.Context, orgSlug, key, value string, diags *diag.Diagnostics) *bitrise.App {
+	tflog.Debug(ctx, "Looking up Bitrise app to import", map[string]interface{}{
+		"organization_slug": orgSlug,
+		key:                 value,
+	})
 
-	// Create an HTTP request with PATCH method
-	httpReq, err := http.NewRequestWithContext(ctx, "PATCH", completeURL, strings.NewReader(payload))
+	apps, err := r.client.ListOrganizationApps(ctx, orgSlug, bitrise.ListAppsParams{})
 	if err != nil {
-		tflog.Error(ctx, "Error creating HTTP request", map[string]interface{}{"error": err.Error()})
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create request: %s", err))
-		return
+		tflog.Error(ctx, "Failed to list organization apps", map[string]interface{}{"error": err.Error()})
+		diags.AddError("API Error", fmt.Sprintf("Failed to list apps of organization %s: %s", orgSlug, err))
+		return nil
 	}
 
-	// Dump the HTTP request details
-	dump, _ := httputil.DumpRequest(httpReq, true)
-	tflog.Debug(ctx, "MODULEDEBUG: HTTP Request", map[string]interface{}{"request": string(dump)})
+	var matches []bitrise.App
+	for _, app := range apps {
+		if key == "repo" && normalizeRepoURL(app.RepoURL) == normalizeRepoURL(value) || key == "title" && app.Title == value {
+			matches = append(matches, app)
+		}
+	}
 
-	// Send the HTTP request
-	httpResp, err := client.Do(httpReq)
-	if err != nil {
-		tflog.Error(ctx, "Error sending HTTP request", map[string]interface{}{"error": err.Error()})
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update app: %s", err))
-		return
+	switch len(matches) {
+	case 0:
+		diags.AddError("App Not Found", fmt.Sprintf("No app of organization %s has %s %q.", orgSlug, key, value))
+		return nil
+	case 1:
+		return &matches[0]
 	}
-	defer httpResp.Body.Close()
 
-	// Read the response body
-	responseBody, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		tflog.Error(ctx, "Error reading response body", map[string]interface{}{"error": err.Error()})
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read response: %s", err))
-		return
+	slugs := make([]string, 0, len(matches))
+	for _, app := range matches {
+		slugs = append(slugs, app.Slug)
 	}
+	diags.AddError("Multiple Apps Found",
+		fmt.Sprintf("Several apps of organization %s have %s %q: %s. Import one of them by slug.", orgSlug, key, value, strings.Join(slugs, ", ")))
+	return nil
+}
 
-	tflog.Debug(ctx, "MODULEDEBUG: Response body", map[string]interface{}{"body": string(responseBody)})
+// setAppModel copies the attributes reported by the API into data. The
+// repository attributes keep their prior value when the API only normalised
+// it, e.g. changed the case or dropped the .git suffix, as the change would
+// otherwise be reported as drift and force a replacement.
+func setAppModel(data *AppResourceModel, app *bitrise.App) {
+	if app.RepoURL != "" && normalizeRepoURL(app.RepoURL) != normalizeRepoURL(data.RepoURL.ValueString()) {
+		data.RepoURL = types.StringValue(app.RepoURL)
+	}
+	data.IsPublic = types.BoolValue(app.IsPublic)
+	if app.RepoOwner != "" && !strings.EqualFold(app.RepoOwner, data.GitOwner.ValueString()) {
+		data.GitOwner = types.StringValue(app.RepoOwner)
+	}
+	if app.RepoSlug != "" && !strings.EqualFold(app.RepoSlug, data.GitRepoSlug.ValueString()) {
+		data.GitRepoSlug = types.StringValue(app.RepoSlug)
+	}
+	if app.Provider != "" {
+		data.Repo = types.StringValue(app.Provider)
+	}
+	if app.Title != "" {
+		data.Title = types.StringValue(app.Title)
+	}
+	if app.DefaultBranch != "" {
+		data.DefaultBranch = types.StringValue(app.DefaultBranch)
+	}
+	// Update ID with the app slug
+	data.Id = types.StringValue(app.Slug)
+}
 
-	// Check response status
-	if httpResp.StatusCode != http.StatusOK {
-		tflog.Error(ctx, "Update request did not succeed", map[string]interface{}{
-			"status": httpResp.Status,
-			"body":   string(responseBody),
-		})
-		resp.Diagnostics.AddError("API Request Error", fmt.Sprintf("Request did not succeed: %s - %s", httpResp.Status, string(responseBody)))
-		return
+// savePartialApp records an app that was registered but could not be fully
+// set up. Returning an error together with the state makes Terraform mark the
+// resource as tainted, so the next apply deletes and re-creates it instead of
+// leaving an orphaned app in Bitrise.
+func savePartialApp(ctx context.Context, data *AppResourceModel, resp *resource.CreateResponse, detail string) {
+	clearUnknownAppAttributes(data)
+
+	// The app has no history yet, so nothing may stop the cleanup
+	data.DeletionProtection = types.BoolValue(false)
+	data.OnDestroy = types.StringValue(appOnDestroyDelete)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, data)...)
+	resp.Diagnostics.AddError(
+		"App Partially Created",
+		fmt.Sprintf("%s\n\nThe app %s was registered in Bitrise and is saved as tainted, the next apply deletes and re-creates it.", detail, data.AppSlug.ValueString()),
+	)
+}
+
+// clearUnknownAppAttributes nulls the computed attributes the API did not
+// report, as state cannot hold unknown values after apply.
+func clearUnknownAppAttributes(data *AppResourceModel) {
+	for _, value := range []*types.String{&data.Repo, &data.GitOwner, &data.GitRepoSlug, &data.Title, &data.DefaultBranch} {
+		if value.IsUnknown() {
+			*value = types.StringNull()
+		}
 	}
+}
 
-	tflog.Info(ctx, "App updated successfully")
+// appSettingsPatch returns the PATCH payload for the configured title and
+// default branch, and whether any of them differs from state. A nil state
+// stands for a freshly registered app.
+func appSettingsPatch(data, state *AppResourceModel) (bitrise.PatchAppParams, bool) {
+	var params bitrise.PatchAppParams
+	changed := false
 
-	// Save updated data into Terraform state
-	resp.State.Set(ctx, &data)
+	if !data.Title.IsUnknown() && !data.Title.IsNull() {
+		params.Title = data.Title.ValueString()
+		changed = changed || state == nil || !data.Title.Equal(state.Title)
+	}
+	if !data.DefaultBranch.IsUnknown() && !data.DefaultBranch.IsNull() {
+		params.DefaultBranch = data.DefaultBranch.ValueString()
+		changed = changed || state == nil || !data.DefaultBranch.Equal(state.DefaultBranch)
+	}
+
+	return params, changed
 }
 
-func (r *AppResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+// appAvatar returns the file name and content of the configured avatar, or
+// an empty content when none is configured.
+func appAvatar(data *AppResourceModel) (string, []byte, error) {
+	var filename string
+	var content []byte
+
+	switch {
+	case !data.AvatarPath.IsNull():
+		var err error
+		if content, err = os.ReadFile(data.AvatarPath.ValueString()); err != nil {
+			return "", nil, fmt.Errorf("unable to read avatar: %w", err)
+		}
+		filename = filepath.Base(data.AvatarPath.ValueString())
+	case !data.AvatarBase64.IsNull():
+		var err error
+		if content, err = base64.StdEncoding.DecodeString(data.AvatarBase64.ValueString()); err != nil {
+			return "", nil, fmt.Errorf("avatar_base64 is not valid base64: %w", err)
+		}
+	default:
+		return "", nil, nil
+	}
+
+	var ext string
+	switch contentType := http.DetectContentType(content); contentType {
+	case "image/png":
+		ext = ".png"
+	case "image/jpeg":
+		ext = ".jpg"
+	case "image/gif":
+		ext = ".gif"
+	default:
+		return "", nil, fmt.Errorf("the avatar must be a PNG, JPEG or GIF image, got %s", contentType)
+	}
+	if filename == "" {
+		filename = "avatar" + ext
+	}
+
+	return filename, content, nil
 }
 
-func handleRequestError(err error, resp *resource.CreateResponse) {
-	// Implementation for handling request error
+func (r *AppResource) uploadAvatar(ctx context.Context, data *AppResourceModel) error {
+	filename, content, err := appAvatar(data)
+	if err != nil {
+		return err
+	}
+
+	return r.client.UploadAppAvatar(ctx, data.AppSlug.ValueString(), filename, content)
 }
 
-func printResponseInfo(httpResp *http.Response) {
-	// Implementation for printing response info
+// setAvatarURL records the avatar URL last read from the API in private state.
+func setAvatarURL(ctx context.Context, private privateState, avatarURL string, diags *diag.Diagnostics) {
+	raw, err := json.Marshal(avatarURL)
+	if err != nil {
+		diags.AddError("Invalid Private State", fmt.Sprintf("Unable to encode the avatar URL: %s", err))
+		return
+	}
+
+	diags.Append(private.SetKey(ctx, appAvatarURLKey, raw)...)
 }
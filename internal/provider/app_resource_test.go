@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"terraform-provider-bitrise/internal/bitrise"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestAppDeleteDisable(t *testing.T) {
+	tests := []struct {
+		name        string
+		disables    bool
+		gone        bool
+		wantError   bool
+		wantRemoved bool
+	}{
+		{
+			name:        "disabled",
+			disables:    true,
+			wantRemoved: true,
+		},
+		{
+			name:      "still enabled",
+			wantError: true,
+		},
+		{
+			name:        "already deleted",
+			gone:        true,
+			wantRemoved: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			app := bitrise.App{Slug: "app-slug"}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.gone || r.URL.Path != "/v0.1/apps/app-slug" {
+					http.NotFound(w, r)
+					return
+				}
+
+				switch r.Method {
+				case http.MethodPatch:
+					var params bitrise.PatchAppParams
+					if err := json.NewDecoder(r.Body).Decode(&params); err != nil || params.IsDisabled == nil {
+						http.Error(w, "expected is_disabled", http.StatusBadRequest)
+						return
+					}
+					app.IsDisabled = tt.disables && *params.IsDisabled
+				case http.MethodGet:
+					_ = json.NewEncoder(w).Encode(map[string]bitrise.App{"data": app})
+				default:
+					http.Error(w, "unexpected method", http.StatusMethodNotAllowed)
+				}
+			}))
+			defer server.Close()
+
+			r := &AppResource{client: bitrise.NewClient(server.URL, server.Client())}
+
+			var schemaResp resource.SchemaResponse
+			r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+			state := tfsdk.State{
+				Schema: schemaResp.Schema,
+				Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+			}
+			for attr, value := range map[string]interface{}{
+				"app_slug":            "app-slug",
+				"on_destroy":          appOnDestroyDisable,
+				"deletion_protection": false,
+			} {
+				if diags := state.SetAttribute(ctx, path.Root(attr), value); diags.HasError() {
+					t.Fatalf("SetAttribute(%s) diagnostics = %v", attr, diags)
+				}
+			}
+
+			req := resource.DeleteRequest{State: state}
+			resp := resource.DeleteResponse{State: state}
+			r.Delete(ctx, req, &resp)
+
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("Delete() diagnostics = %v, want error %t", resp.Diagnostics, tt.wantError)
+			}
+			if removed := resp.State.Raw.IsNull(); removed != tt.wantRemoved {
+				t.Errorf("Delete() removed the app from state = %t, want %t", removed, tt.wantRemoved)
+			}
+		})
+	}
+}
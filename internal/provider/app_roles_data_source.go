@@ -2,10 +2,9 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
+
+	"terraform-provider-bitrise/internal/bitrise"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -15,29 +14,19 @@ import (
 
 var _ datasource.DataSource = &AppRolesDataSource{}
 
-func NewAppRolesDataSource(clientCreator func(endpoint, token string) *http.Client, endpoint, token string) *AppRolesDataSource {
-	return &AppRolesDataSource{
-		clientCreator: clientCreator,
-		endpoint:      endpoint,
-		token:         token,
-	}
+func NewAppRolesDataSource() datasource.DataSource {
+	return &AppRolesDataSource{}
 }
 
 type AppRolesDataSource struct {
-	clientCreator func(endpoint, token string) *http.Client
-	endpoint      string
-	token         string
+	client *bitrise.Client
 }
 
 type AppRolesDataSourceModel struct {
-	AppSlug             types.String   `tfsdk:"app_slug"`
-	RoleName            types.String   `tfsdk:"role_name"`
-	ID                  types.String   `tfsdk:"id"`
-	Groups              []types.String `tfsdk:"groups"`
-}
-
-type GroupRolesResponse struct {
-	Groups []string `json:"groups"`
+	AppSlug  types.String   `tfsdk:"app_slug"`
+	RoleName types.String   `tfsdk:"role_name"`
+	ID       types.String   `tfsdk:"id"`
+	Groups   []types.String `tfsdk:"groups"`
 }
 
 func (d *AppRolesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -74,19 +63,21 @@ func (d *AppRolesDataSource) Configure(ctx context.Context, req datasource.Confi
 		return
 	}
 
-	clientCreator, ok := req.ProviderData.(func(endpoint, token string) *http.Client)
+	client, ok := req.ProviderData.(*bitrise.Client)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected func(endpoint, token string) *http.Client, got: %T", req.ProviderData),
+			fmt.Sprintf("Expected *bitrise.Client, got: %T", req.ProviderData),
 		)
 		return
 	}
 
-	d.clientCreator = clientCreator
+	d.client = client
 }
 
 func (d *AppRolesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.client.LogContext(ctx)
+
 	var data AppRolesDataSourceModel
 
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
@@ -102,49 +93,16 @@ func (d *AppRolesDataSource) Read(ctx context.Context, req datasource.ReadReques
 		"role_name": roleName,
 	})
 
-	client := d.clientCreator(d.endpoint, d.token)
-	url := fmt.Sprintf("%s/v0.1/apps/%s/roles/%s", d.endpoint, appSlug, roleName)
-
-	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating HTTP request", err.Error())
-		return
-	}
-
-	httpResp, err := client.Do(httpReq)
-	if err != nil {
-		resp.Diagnostics.AddError("Error sending HTTP request", err.Error())
-		return
-	}
-	defer httpResp.Body.Close()
-
-	responseBody, err := io.ReadAll(httpResp.Body)
+	apiGroups, err := d.client.GetRoleGroups(ctx, appSlug, roleName)
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading response body", err.Error())
-		return
-	}
-
-	if httpResp.StatusCode != http.StatusOK {
-		tflog.Error(ctx, "Failed to read role groups", map[string]interface{}{
-			"status": httpResp.Status,
-			"body":   string(responseBody),
-		})
-		resp.Diagnostics.AddError(
-			"API Error",
-			fmt.Sprintf("Failed to read role groups: %s - %s", httpResp.Status, string(responseBody)),
-		)
-		return
-	}
-
-	var rolesResp GroupRolesResponse
-	if err := json.Unmarshal(responseBody, &rolesResp); err != nil {
-		resp.Diagnostics.AddError("Error parsing response", err.Error())
+		tflog.Error(ctx, "Failed to read role groups", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to read role groups: %s", err))
 		return
 	}
 
 	// Convert API response to terraform model
-	groups := make([]types.String, 0, len(rolesResp.Groups))
-	for _, group := range rolesResp.Groups {
+	groups := make([]types.String, 0, len(apiGroups))
+	for _, group := range apiGroups {
 		groups = append(groups, types.StringValue(group))
 	}
 
@@ -2,12 +2,11 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"strings"
 
+	"terraform-provider-bitrise/internal/bitrise"
+
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -21,18 +20,12 @@ import (
 var _ resource.Resource = &AppRolesResource{}
 var _ resource.ResourceWithImportState = &AppRolesResource{}
 
-func NewAppRolesResource(clientCreator func(endpoint, token string) *http.Client, endpoint, token string) *AppRolesResource {
-	return &AppRolesResource{
-		clientCreator: clientCreator,
-		endpoint:      endpoint,
-		token:         token,
-	}
+func NewAppRolesResource() resource.Resource {
+	return &AppRolesResource{}
 }
 
 type AppRolesResource struct {
-	clientCreator func(endpoint, token string) *http.Client
-	endpoint      string
-	token         string
+	client *bitrise.Client
 }
 
 type AppRolesResourceModel struct {
@@ -42,14 +35,6 @@ type AppRolesResourceModel struct {
 	Groups   []types.String `tfsdk:"groups"`
 }
 
-type GroupRolesUpdateRequest struct {
-	Groups []string `json:"groups"`
-}
-
-type GroupRolesReadResponse struct {
-	Groups []string `json:"groups"`
-}
-
 func (r *AppRolesResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_app_roles"
 }
@@ -93,19 +78,21 @@ func (r *AppRolesResource) Configure(ctx context.Context, req resource.Configure
 		return
 	}
 
-	clientCreator, ok := req.ProviderData.(func(endpoint, token string) *http.Client)
+	client, ok := req.ProviderData.(*bitrise.Client)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected func(endpoint, token string) *http.Client, got: %T", req.ProviderData),
+			fmt.Sprintf("Expected *bitrise.Client, got: %T", req.ProviderData),
 		)
 		return
 	}
 
-	r.clientCreator = clientCreator
+	r.client = client
 }
 
 func (r *AppRolesResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.LogContext(ctx)
+
 	var data AppRolesResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
@@ -127,11 +114,7 @@ func (r *AppRolesResource) Create(ctx context.Context, req resource.CreateReques
 		groups = append(groups, group.ValueString())
 	}
 
-	rolesReq := GroupRolesUpdateRequest{
-		Groups: groups,
-	}
-
-	if err := r.updateRoleGroups(ctx, appSlug, roleName, rolesReq, &resp.Diagnostics); err != nil {
+	if err := r.updateRoleGroups(ctx, appSlug, roleName, groups, &resp.Diagnostics); err != nil {
 		return
 	}
 
@@ -146,6 +129,8 @@ func (r *AppRolesResource) Create(ctx context.Context, req resource.CreateReques
 }
 
 func (r *AppRolesResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.LogContext(ctx)
+
 	var data AppRolesResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
@@ -160,23 +145,8 @@ func (r *AppRolesResource) Read(ctx context.Context, req resource.ReadRequest, r
 		"role_name": roleName,
 	})
 
-	client := r.clientCreator(r.endpoint, r.token)
-	url := fmt.Sprintf("%s/v0.1/apps/%s/roles/%s", r.endpoint, appSlug, roleName)
-
-	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating HTTP request", err.Error())
-		return
-	}
-
-	httpResp, err := client.Do(httpReq)
-	if err != nil {
-		resp.Diagnostics.AddError("Error sending HTTP request", err.Error())
-		return
-	}
-	defer httpResp.Body.Close()
-
-	if httpResp.StatusCode == http.StatusNotFound {
+	apiGroups, err := r.client.GetRoleGroups(ctx, appSlug, roleName)
+	if bitrise.IsNotFound(err) {
 		tflog.Info(ctx, "App or role not found, removing from state", map[string]interface{}{
 			"app_slug":  appSlug,
 			"role_name": roleName,
@@ -184,34 +154,15 @@ func (r *AppRolesResource) Read(ctx context.Context, req resource.ReadRequest, r
 		resp.State.RemoveResource(ctx)
 		return
 	}
-
-	responseBody, err := io.ReadAll(httpResp.Body)
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading response body", err.Error())
-		return
-	}
-
-	if httpResp.StatusCode != http.StatusOK {
-		tflog.Error(ctx, "Failed to read role groups", map[string]interface{}{
-			"status": httpResp.Status,
-			"body":   string(responseBody),
-		})
-		resp.Diagnostics.AddError(
-			"API Error",
-			fmt.Sprintf("Failed to read role groups: %s - %s", httpResp.Status, string(responseBody)),
-		)
-		return
-	}
-
-	var rolesResp GroupRolesReadResponse
-	if err := json.Unmarshal(responseBody, &rolesResp); err != nil {
-		resp.Diagnostics.AddError("Error parsing response", err.Error())
+		tflog.Error(ctx, "Failed to read role groups", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to read role groups: %s", err))
 		return
 	}
 
 	// Convert API response to terraform model
-	groups := make([]types.String, 0, len(rolesResp.Groups))
-	for _, group := range rolesResp.Groups {
+	groups := make([]types.String, 0, len(apiGroups))
+	for _, group := range apiGroups {
 		groups = append(groups, types.StringValue(group))
 	}
 
@@ -221,6 +172,8 @@ func (r *AppRolesResource) Read(ctx context.Context, req resource.ReadRequest, r
 }
 
 func (r *AppRolesResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.client.LogContext(ctx)
+
 	var data AppRolesResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
@@ -242,11 +195,7 @@ func (r *AppRolesResource) Update(ctx context.Context, req resource.UpdateReques
 		groups = append(groups, group.ValueString())
 	}
 
-	rolesReq := GroupRolesUpdateRequest{
-		Groups: groups,
-	}
-
-	if err := r.updateRoleGroups(ctx, appSlug, roleName, rolesReq, &resp.Diagnostics); err != nil {
+	if err := r.updateRoleGroups(ctx, appSlug, roleName, groups, &resp.Diagnostics); err != nil {
 		return
 	}
 
@@ -259,6 +208,8 @@ func (r *AppRolesResource) Update(ctx context.Context, req resource.UpdateReques
 }
 
 func (r *AppRolesResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.client.LogContext(ctx)
+
 	var data AppRolesResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
@@ -274,11 +225,7 @@ func (r *AppRolesResource) Delete(ctx context.Context, req resource.DeleteReques
 	})
 
 	// To "delete" role groups, we set it to an empty list
-	rolesReq := GroupRolesUpdateRequest{
-		Groups: []string{},
-	}
-
-	if err := r.updateRoleGroups(ctx, appSlug, roleName, rolesReq, &resp.Diagnostics); err != nil {
+	if err := r.updateRoleGroups(ctx, appSlug, roleName, []string{}, &resp.Diagnostics); err != nil {
 		return
 	}
 
@@ -305,52 +252,18 @@ func (r *AppRolesResource) ImportState(ctx context.Context, req resource.ImportS
 }
 
 // updateRoleGroups is a helper function to update role groups via the Bitrise API
-func (r *AppRolesResource) updateRoleGroups(ctx context.Context, appSlug, roleName string, rolesReq GroupRolesUpdateRequest, diags *diag.Diagnostics) error {
-	client := r.clientCreator(r.endpoint, r.token)
-	url := fmt.Sprintf("%s/v0.1/apps/%s/roles/%s", r.endpoint, appSlug, roleName)
-
-	payloadJSON, err := json.Marshal(rolesReq)
-	if err != nil {
-		diags.AddError("Error marshaling request", err.Error())
-		return err
-	}
-
-	tflog.Debug(ctx, "Sending PUT request to update role groups", map[string]interface{}{
-		"url":     url,
-		"payload": string(payloadJSON),
+func (r *AppRolesResource) updateRoleGroups(ctx context.Context, appSlug, roleName string, groups []string, diags *diag.Diagnostics) error {
+	tflog.Debug(ctx, "Replacing role groups", map[string]interface{}{
+		"app_slug":  appSlug,
+		"role_name": roleName,
+		"groups":    groups,
 	})
 
-	httpReq, err := http.NewRequestWithContext(ctx, "PUT", url, strings.NewReader(string(payloadJSON)))
-	if err != nil {
-		diags.AddError("Error creating HTTP request", err.Error())
+	if err := r.client.PutRoleGroups(ctx, appSlug, roleName, groups); err != nil {
+		tflog.Error(ctx, "Failed to update role groups", map[string]interface{}{"error": err.Error()})
+		diags.AddError("API Error", fmt.Sprintf("Failed to update role groups: %s", err))
 		return err
 	}
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	httpResp, err := client.Do(httpReq)
-	if err != nil {
-		diags.AddError("Error sending HTTP request", err.Error())
-		return err
-	}
-	defer httpResp.Body.Close()
-
-	responseBody, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		diags.AddError("Error reading response body", err.Error())
-		return err
-	}
-
-	if httpResp.StatusCode != http.StatusOK {
-		tflog.Error(ctx, "Failed to update role groups", map[string]interface{}{
-			"status": httpResp.Status,
-			"body":   string(responseBody),
-		})
-		diags.AddError(
-			"API Error",
-			fmt.Sprintf("Failed to update role groups: %s - %s", httpResp.Status, string(responseBody)),
-		)
-		return fmt.Errorf("API error: %s", httpResp.Status)
-	}
 
 	return nil
 }
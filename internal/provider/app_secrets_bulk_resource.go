@@ -0,0 +1,506 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"terraform-provider-bitrise/internal/bitrise"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ resource.Resource = &AppSecretsBulkResource{}
+var _ resource.ResourceWithImportState = &AppSecretsBulkResource{}
+var _ resource.ResourceWithModifyPlan = &AppSecretsBulkResource{}
+
+func NewAppSecretsBulkResource() resource.Resource {
+	return &AppSecretsBulkResource{}
+}
+
+// AppSecretsBulkResource manages every secret of an app in one resource, as
+// opposed to AppSecretsResource which manages a single secret.
+type AppSecretsBulkResource struct {
+	client *bitrise.Client
+}
+
+type AppSecretsBulkResourceModel struct {
+	ID               types.String                         `tfsdk:"id"`
+	AppSlug          types.String                         `tfsdk:"app_slug"`
+	Secrets          map[string]AppSecretsBulkSecretModel `tfsdk:"secrets"`
+	Authoritative    types.Bool                           `tfsdk:"authoritative"`
+	UnmanagedSecrets types.List                           `tfsdk:"unmanaged_secrets"`
+}
+
+type AppSecretsBulkSecretModel struct {
+	Value                    types.String `tfsdk:"value"`
+	IsProtected              types.Bool   `tfsdk:"is_protected"`
+	IsExposedForPullRequests types.Bool   `tfsdk:"is_exposed_for_pull_requests"`
+	ExpandInStepInputs       types.Bool   `tfsdk:"expand_in_step_inputs"`
+}
+
+func (m AppSecretsBulkSecretModel) equal(other AppSecretsBulkSecretModel) bool {
+	return m.Value.Equal(other.Value) &&
+		m.IsProtected.Equal(other.IsProtected) &&
+		m.IsExposedForPullRequests.Equal(other.IsExposedForPullRequests) &&
+		m.ExpandInStepInputs.Equal(other.ExpandInStepInputs)
+}
+
+func (r *AppSecretsBulkResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_app_secrets"
+}
+
+func (r *AppSecretsBulkResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages the secrets of a Bitrise application as a whole. Secrets found on the app but missing from `secrets` are reported in `unmanaged_secrets`, and deleted when `authoritative` is set.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The identifier of the resource (app_slug)",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"app_slug": schema.StringAttribute{
+				MarkdownDescription: "The slug of the Bitrise app",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"secrets": schema.MapNestedAttribute{
+				MarkdownDescription: "The secrets of the app, keyed by name",
+				Required:            true,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(secretNameValidator),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"value": schema.StringAttribute{
+							MarkdownDescription: "The value of the secret",
+							Required:            true,
+							Sensitive:           true,
+						},
+						"is_protected": schema.BoolAttribute{
+							MarkdownDescription: "If true, the secret value cannot be retrieved via the API. Default: false",
+							Optional:            true,
+							Computed:            true,
+							Default:             booldefault.StaticBool(false),
+						},
+						"is_exposed_for_pull_requests": schema.BoolAttribute{
+							MarkdownDescription: "If true, the secret will be available for pull request builds. Default: false",
+							Optional:            true,
+							Computed:            true,
+							Default:             booldefault.StaticBool(false),
+						},
+						"expand_in_step_inputs": schema.BoolAttribute{
+							MarkdownDescription: "If true, variable expansion will be enabled for this secret in step inputs. Default: true",
+							Optional:            true,
+							Computed:            true,
+							Default:             booldefault.StaticBool(true),
+						},
+					},
+				},
+			},
+			"authoritative": schema.BoolAttribute{
+				MarkdownDescription: "If true, secrets of the app that are not listed in `secrets` are deleted. Default: false",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"unmanaged_secrets": schema.ListAttribute{
+				MarkdownDescription: "Names of the secrets of the app that are not listed in `secrets`",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+		},
+	}
+}
+
+func (r *AppSecretsBulkResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*bitrise.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *bitrise.Client, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+// ModifyPlan predicts unmanaged_secrets: authoritative resources delete every
+// unmanaged secret, so an app with extra secrets gets an update planned.
+func (r *AppSecretsBulkResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var secrets types.Map
+	var authoritative types.Bool
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("secrets"), &secrets)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("authoritative"), &authoritative)...)
+	if resp.Diagnostics.HasError() || secrets.IsUnknown() || authoritative.IsUnknown() {
+		return
+	}
+
+	var unmanaged types.List
+	switch {
+	case authoritative.ValueBool():
+		unmanaged = types.ListValueMust(types.StringType, nil)
+	case req.State.Raw.IsNull():
+		return
+	default:
+		var stateUnmanaged []string
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("unmanaged_secrets"), &stateUnmanaged)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		// Secrets listed in the configuration from now on become managed
+		managed := secrets.Elements()
+		names := slices.DeleteFunc(append([]string{}, stateUnmanaged...), func(name string) bool {
+			_, ok := managed[name]
+			return ok
+		})
+
+		var diags diag.Diagnostics
+		unmanaged, diags = types.ListValueFrom(ctx, types.StringType, names)
+		resp.Diagnostics.Append(diags...)
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("unmanaged_secrets"), unmanaged)...)
+}
+
+func (r *AppSecretsBulkResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.LogContext(ctx)
+
+	var data AppSecretsBulkResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Creating Bitrise app secrets", map[string]interface{}{
+		"app_slug": data.AppSlug.ValueString(),
+		"count":    len(data.Secrets),
+	})
+
+	data.ID = data.AppSlug
+
+	current := map[string]AppSecretsBulkSecretModel{}
+	writes := secretWrites{}
+	unmanaged, err := r.apply(ctx, &data, current, writes)
+	defer saveSecretWrites(ctx, resp.Private, writes, &resp.Diagnostics)
+	if err != nil {
+		tflog.Error(ctx, "Failed to create secrets", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to create secrets: %s", err))
+
+		// Secrets written so far are kept in state, tainted, so that the next
+		// apply removes them before starting over
+		if len(current) > 0 {
+			data.Secrets = current
+			data.UnmanagedSecrets = types.ListValueMust(types.StringType, nil)
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		}
+		return
+	}
+
+	resp.Diagnostics.Append(setUnmanagedSecrets(ctx, &data, unmanaged)...)
+	r.recordWrites(ctx, &data, writes)
+
+	tflog.Info(ctx, "Successfully created Bitrise app secrets", map[string]interface{}{"id": data.ID.ValueString()})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AppSecretsBulkResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.LogContext(ctx)
+
+	var data AppSecretsBulkResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	appSlug := data.AppSlug.ValueString()
+	tflog.Debug(ctx, "Reading Bitrise app secrets", map[string]interface{}{"app_slug": appSlug})
+
+	secrets, err := r.client.ListSecrets(ctx, appSlug)
+	if bitrise.IsNotFound(err) {
+		tflog.Info(ctx, "App not found, removing secrets from state", map[string]interface{}{"app_slug": appSlug})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		tflog.Error(ctx, "Failed to list secrets", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to list secrets: %s", err))
+		return
+	}
+
+	// An imported resource adopts every secret of the app
+	imported := data.Secrets == nil
+	writes := loadSecretWrites(ctx, req.Private, &resp.Diagnostics)
+
+	managed := make(map[string]AppSecretsBulkSecretModel, len(data.Secrets))
+	var unmanaged []string
+	for _, secret := range secrets {
+		known, ok := data.Secrets[secret.Name]
+		if !ok && !imported {
+			unmanaged = append(unmanaged, secret.Name)
+			continue
+		}
+
+		// Protected values are never returned, the value from state is kept
+		// unless the secret was written outside of Terraform, in which case
+		// clearing it plans the configured value to be written again
+		value := known.Value
+		if !secret.IsProtected && secret.Value != "" || imported {
+			value = types.StringValue(secret.Value)
+		}
+		if writes.changedOutside(&secret) {
+			addProtectedSecretDriftWarning(&resp.Diagnostics, "app "+appSlug, secret.Name)
+			value = types.StringNull()
+		} else {
+			writes.observe(&secret)
+		}
+
+		managed[secret.Name] = AppSecretsBulkSecretModel{
+			Value:                    value,
+			IsProtected:              types.BoolValue(secret.IsProtected),
+			IsExposedForPullRequests: types.BoolValue(secret.IsExposedForPullRequests),
+			ExpandInStepInputs:       types.BoolValue(secret.ExpandInStepInputs),
+		}
+	}
+
+	// Managed secrets deleted outside of Terraform are dropped, so that the
+	// next plan creates them again
+	data.Secrets = managed
+	maps.DeleteFunc(writes, func(name string, _ string) bool {
+		_, ok := managed[name]
+		return !ok
+	})
+	saveSecretWrites(ctx, resp.Private, writes, &resp.Diagnostics)
+	data.ID = data.AppSlug
+	if data.Authoritative.IsNull() {
+		data.Authoritative = types.BoolValue(false)
+	}
+	data.UnmanagedSecrets = types.ListNull(types.StringType)
+	resp.Diagnostics.Append(setUnmanagedSecrets(ctx, &data, unmanaged)...)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AppSecretsBulkResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.client.LogContext(ctx)
+
+	var data, state AppSecretsBulkResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Updating Bitrise app secrets", map[string]interface{}{
+		"app_slug": data.AppSlug.ValueString(),
+		"count":    len(data.Secrets),
+	})
+
+	current := maps.Clone(state.Secrets)
+	if current == nil {
+		current = map[string]AppSecretsBulkSecretModel{}
+	}
+
+	writes := loadSecretWrites(ctx, req.Private, &resp.Diagnostics)
+	unmanaged, err := r.apply(ctx, &data, current, writes)
+	defer saveSecretWrites(ctx, resp.Private, writes, &resp.Diagnostics)
+	if err != nil {
+		tflog.Error(ctx, "Failed to update secrets", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to update secrets: %s", err))
+
+		// Record the secrets written before the failure
+		state.Secrets = current
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		return
+	}
+
+	resp.Diagnostics.Append(setUnmanagedSecrets(ctx, &data, unmanaged)...)
+	r.recordWrites(ctx, &data, writes)
+
+	tflog.Info(ctx, "Successfully updated Bitrise app secrets")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AppSecretsBulkResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.client.LogContext(ctx)
+
+	var data AppSecretsBulkResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	appSlug := data.AppSlug.ValueString()
+	tflog.Debug(ctx, "Deleting Bitrise app secrets", map[string]interface{}{"app_slug": appSlug})
+
+	// Unmanaged secrets are left alone, even for authoritative resources
+	for _, name := range slices.Sorted(maps.Keys(data.Secrets)) {
+		err := r.client.DeleteSecret(ctx, appSlug, name)
+		// 404 means already deleted, which is fine
+		if err != nil && !bitrise.IsNotFound(err) {
+			tflog.Error(ctx, "Failed to delete secret", map[string]interface{}{"error": err.Error()})
+			resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to delete secret %s: %s", name, err))
+			return
+		}
+	}
+
+	tflog.Info(ctx, "Successfully deleted Bitrise app secrets")
+}
+
+func (r *AppSecretsBulkResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if req.ID == "" || strings.Contains(req.ID, "/") {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("Import ID must be the app slug, got: %s", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("app_slug"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
+
+// apply makes the secrets of the app match data. current holds the secrets
+// known to be on the app and is updated after every request, so that it
+// describes the app when a request fails. The write records of the secrets
+// it touches are dropped from writes. The names of the secrets left
+// unmanaged are returned.
+func (r *AppSecretsBulkResource) apply(ctx context.Context, data *AppSecretsBulkResourceModel, current map[string]AppSecretsBulkSecretModel, writes secretWrites) ([]string, error) {
+	appSlug := data.AppSlug.ValueString()
+
+	// The list tells apart secrets to create from secrets to update, which
+	// also adopts secrets that already exist when they get managed
+	secrets, err := r.client.ListSecrets(ctx, appSlug)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list secrets: %w", err)
+	}
+	existing := make(map[string]bool, len(secrets))
+	for _, secret := range secrets {
+		existing[secret.Name] = true
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(current)) {
+		if _, ok := data.Secrets[name]; ok {
+			continue
+		}
+		if err := r.client.DeleteSecret(ctx, appSlug, name); err != nil && !bitrise.IsNotFound(err) {
+			return nil, fmt.Errorf("unable to delete secret %s: %w", name, err)
+		}
+		delete(current, name)
+		delete(existing, name)
+		delete(writes, name)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(data.Secrets)) {
+		want := data.Secrets[name]
+		if have, ok := current[name]; ok && existing[name] && have.equal(want) {
+			continue
+		}
+
+		delete(writes, name)
+		if existing[name] {
+			isProtected := want.IsProtected.ValueBool()
+			isExposed := want.IsExposedForPullRequests.ValueBool()
+			expandInputs := want.ExpandInStepInputs.ValueBool()
+			err = r.client.UpdateSecret(ctx, appSlug, name, bitrise.UpdateSecretParams{
+				Value:                    want.Value.ValueString(),
+				IsProtected:              &isProtected,
+				IsExposedForPullRequests: &isExposed,
+				ExpandInStepInputs:       &expandInputs,
+			})
+		} else {
+			_, err = r.client.CreateSecret(ctx, appSlug, bitrise.CreateSecretParams{
+				Name:                     name,
+				Value:                    want.Value.ValueString(),
+				IsProtected:              want.IsProtected.ValueBool(),
+				IsExposedForPullRequests: want.IsExposedForPullRequests.ValueBool(),
+				ExpandInStepInputs:       want.ExpandInStepInputs.ValueBool(),
+			})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to write secret %s: %w", name, err)
+		}
+		current[name] = want
+		existing[name] = true
+	}
+
+	var unmanaged []string
+	for _, name := range slices.Sorted(maps.Keys(existing)) {
+		if _, ok := data.Secrets[name]; ok {
+			continue
+		}
+		if !data.Authoritative.ValueBool() {
+			unmanaged = append(unmanaged, name)
+			continue
+		}
+
+		tflog.Info(ctx, "Deleting unmanaged secret", map[string]interface{}{"app_slug": appSlug, "name": name})
+		if err := r.client.DeleteSecret(ctx, appSlug, name); err != nil && !bitrise.IsNotFound(err) {
+			return nil, fmt.Errorf("unable to delete unmanaged secret %s: %w", name, err)
+		}
+	}
+
+	return unmanaged, nil
+}
+
+// recordWrites records the update timestamps of the managed secrets after an
+// apply. Without timestamps the records stay empty, and the next Read adopts
+// the ones it finds.
+func (r *AppSecretsBulkResource) recordWrites(ctx context.Context, data *AppSecretsBulkResourceModel, writes secretWrites) {
+	secrets, err := r.client.ListSecrets(ctx, data.AppSlug.ValueString())
+	if err != nil {
+		tflog.Warn(ctx, "Unable to read back written secrets", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	clear(writes)
+	for _, secret := range secrets {
+		if _, ok := data.Secrets[secret.Name]; ok && secret.UpdatedAt != "" {
+			writes[secret.Name] = secret.UpdatedAt
+		}
+	}
+}
+
+// setUnmanagedSecrets stores the unmanaged secret names, keeping the planned
+// value when it is known so that a secret added between plan and apply does
+// not make the result inconsistent with the plan.
+func setUnmanagedSecrets(ctx context.Context, data *AppSecretsBulkResourceModel, unmanaged []string) diag.Diagnostics {
+	if !data.UnmanagedSecrets.IsUnknown() && !data.UnmanagedSecrets.IsNull() {
+		return nil
+	}
+
+	if unmanaged == nil {
+		unmanaged = []string{}
+	}
+
+	list, diags := types.ListValueFrom(ctx, types.StringType, unmanaged)
+	data.UnmanagedSecrets = list
+	return diags
+}
@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"terraform-provider-bitrise/internal/bitrise"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ datasource.DataSource = &AppSecretsDataSource{}
+
+func NewAppSecretsDataSource() datasource.DataSource {
+	return &AppSecretsDataSource{}
+}
+
+type AppSecretsDataSource struct {
+	client *bitrise.Client
+}
+
+type AppSecretsDataSourceModel struct {
+	ID        types.String         `tfsdk:"id"`
+	AppSlug   types.String         `tfsdk:"app_slug"`
+	NameRegex types.String         `tfsdk:"name_regex"`
+	Secrets   []AppSecretItemModel `tfsdk:"secrets"`
+}
+
+type AppSecretItemModel struct {
+	Name                     types.String `tfsdk:"name"`
+	Value                    types.String `tfsdk:"value"`
+	IsProtected              types.Bool   `tfsdk:"is_protected"`
+	IsExposedForPullRequests types.Bool   `tfsdk:"is_exposed_for_pull_requests"`
+	ExpandInStepInputs       types.Bool   `tfsdk:"expand_in_step_inputs"`
+}
+
+func (d *AppSecretsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_app_secrets"
+}
+
+func (d *AppSecretsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the secrets of a Bitrise app with their settings, e.g. to audit which secrets are exposed to pull requests.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Data source identifier (app_slug)",
+				Computed:            true,
+			},
+			"app_slug": schema.StringAttribute{
+				MarkdownDescription: "The slug of the Bitrise app",
+				Required:            true,
+			},
+			"name_regex": schema.StringAttribute{
+				MarkdownDescription: "Only list secrets whose name matches this regular expression",
+				Optional:            true,
+			},
+			"secrets": schema.ListNestedAttribute{
+				MarkdownDescription: "The matching secrets, sorted by name",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name (key) of the secret",
+							Computed:            true,
+						},
+						"value": schema.StringAttribute{
+							MarkdownDescription: "The value of the secret, null for protected secrets",
+							Computed:            true,
+							Sensitive:           true,
+						},
+						"is_protected": schema.BoolAttribute{
+							MarkdownDescription: "Whether the secret value cannot be retrieved via the API",
+							Computed:            true,
+						},
+						"is_exposed_for_pull_requests": schema.BoolAttribute{
+							MarkdownDescription: "Whether the secret is available for pull request builds",
+							Computed:            true,
+						},
+						"expand_in_step_inputs": schema.BoolAttribute{
+							MarkdownDescription: "Whether variable expansion is enabled for the secret in step inputs",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *AppSecretsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*bitrise.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *bitrise.Client, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *AppSecretsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.client.LogContext(ctx)
+
+	var data AppSecretsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var nameRegex *regexp.Regexp
+	if !data.NameRegex.IsNull() {
+		var err error
+		if nameRegex, err = regexp.Compile(data.NameRegex.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("name_regex"), "Invalid Regular Expression", err.Error())
+			return
+		}
+	}
+
+	appSlug := data.AppSlug.ValueString()
+	tflog.Debug(ctx, "Reading Bitrise app secrets", map[string]interface{}{"app_slug": appSlug})
+
+	secrets, err := d.client.ListSecrets(ctx, appSlug)
+	if err != nil {
+		tflog.Error(ctx, "Failed to list secrets", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to list secrets of app %s: %s", appSlug, err))
+		return
+	}
+
+	slices.SortFunc(secrets, func(a, b bitrise.Secret) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	items := make([]AppSecretItemModel, 0, len(secrets))
+	for _, secret := range secrets {
+		if nameRegex != nil && !nameRegex.MatchString(secret.Name) {
+			continue
+		}
+
+		// Protected values are never returned by the API
+		value := types.StringNull()
+		if !secret.IsProtected {
+			value = types.StringValue(secret.Value)
+		}
+
+		items = append(items, AppSecretItemModel{
+			Name:                     types.StringValue(secret.Name),
+			Value:                    value,
+			IsProtected:              types.BoolValue(secret.IsProtected),
+			IsExposedForPullRequests: types.BoolValue(secret.IsExposedForPullRequests),
+			ExpandInStepInputs:       types.BoolValue(secret.ExpandInStepInputs),
+		})
+	}
+
+	data.ID = data.AppSlug
+	data.Secrets = items
+
+	tflog.Debug(ctx, "Successfully read Bitrise app secrets", map[string]interface{}{
+		"count": len(items),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
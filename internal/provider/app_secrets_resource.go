@@ -2,71 +2,73 @@ package provider
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
+	"regexp"
 	"strings"
 
+	"terraform-provider-bitrise/internal/bitrise"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var _ resource.Resource = &AppSecretsResource{}
 var _ resource.ResourceWithImportState = &AppSecretsResource{}
+var _ resource.ResourceWithConfigValidators = &AppSecretsResource{}
+var _ resource.ResourceWithModifyPlan = &AppSecretsResource{}
 
-func NewAppSecretsResource(clientCreator func(endpoint, token string) *http.Client, endpoint, token string) *AppSecretsResource {
-	return &AppSecretsResource{
-		clientCreator: clientCreator,
-		endpoint:      endpoint,
-		token:         token,
-	}
-}
+// secretNamePattern follows the Bitrise rules for environment variable keys.
+var secretNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
 
-type AppSecretsResource struct {
-	clientCreator func(endpoint, token string) *http.Client
-	endpoint      string
-	token         string
-}
+// secretNameValidator rejects secret names Bitrise would refuse.
+var secretNameValidator = stringvalidator.RegexMatches(
+	secretNamePattern,
+	"must start with a letter or an underscore and contain only letters, digits and underscores",
+)
 
-type AppSecretsResourceModel struct {
-	AppSlug                      types.String `tfsdk:"app_slug"`
-	Name                         types.String `tfsdk:"name"`
-	Value                        types.String `tfsdk:"value"`
-	IsProtected                  types.Bool   `tfsdk:"is_protected"`
-	IsExposedForPullRequests     types.Bool   `tfsdk:"is_exposed_for_pull_requests"`
-	ExpandInStepInputs           types.Bool   `tfsdk:"expand_in_step_inputs"`
-	ID                           types.String `tfsdk:"id"`
+// unprotectsSecret reports whether a plan removes the protection of a secret,
+// which Bitrise rejects, so the secret has to be replaced.
+func unprotectsSecret(state, plan types.Bool) bool {
+	return state.ValueBool() && !plan.IsUnknown() && !plan.ValueBool()
 }
 
-type SecretCreateRequest struct {
-	Name                     string `json:"name"`
-	Value                    string `json:"value"`
-	IsProtected              bool   `json:"is_protected,omitempty"`
-	IsExposedForPullRequests bool   `json:"is_exposed_for_pull_requests,omitempty"`
-	ExpandInStepInputs       bool   `json:"expand_in_step_inputs,omitempty"`
+// secretValueHashKey is the private state key holding the salted hash of the
+// last write-only value, which is never stored itself.
+const secretValueHashKey = "value_wo_hash"
+
+func NewAppSecretsResource() resource.Resource {
+	return &AppSecretsResource{}
 }
 
-type SecretUpdateRequest struct {
-	Value                    string `json:"value,omitempty"`
-	IsProtected              *bool  `json:"is_protected,omitempty"`
-	IsExposedForPullRequests *bool  `json:"is_exposed_for_pull_requests,omitempty"`
-	ExpandInStepInputs       *bool  `json:"expand_in_step_inputs,omitempty"`
+type AppSecretsResource struct {
+	client *bitrise.Client
 }
 
-type SecretResponse struct {
-	Name                     string `json:"name"`
-	Value                    string `json:"value,omitempty"`
-	IsProtected              bool   `json:"is_protected"`
-	IsExposedForPullRequests bool   `json:"is_exposed_for_pull_requests"`
-	ExpandInStepInputs       bool   `json:"expand_in_step_inputs"`
-	ID                       string `json:"id"`
+type AppSecretsResourceModel struct {
+	AppSlug                  types.String `tfsdk:"app_slug"`
+	Name                     types.String `tfsdk:"name"`
+	Value                    types.String `tfsdk:"value"`
+	ValueWO                  types.String `tfsdk:"value_wo"`
+	ValueWOVersion           types.Int64  `tfsdk:"value_wo_version"`
+	IsProtected              types.Bool   `tfsdk:"is_protected"`
+	IsExposedForPullRequests types.Bool   `tfsdk:"is_exposed_for_pull_requests"`
+	ExpandInStepInputs       types.Bool   `tfsdk:"expand_in_step_inputs"`
+	ID                       types.String `tfsdk:"id"`
 }
 
 func (r *AppSecretsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -85,19 +87,32 @@ func (r *AppSecretsResource) Schema(ctx context.Context, req resource.SchemaRequ
 				},
 			},
 			"name": schema.StringAttribute{
-				MarkdownDescription: "The name (key) of the secret",
+				MarkdownDescription: "The name (key) of the secret. Must start with a letter or an underscore and contain only letters, digits and underscores.",
 				Required:            true,
+				Validators: []validator.String{
+					secretNameValidator,
+				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
 			"value": schema.StringAttribute{
-				MarkdownDescription: "The value of the secret",
-				Required:            true,
+				MarkdownDescription: "The value of the secret, stored in state. Exactly one of `value` and `value_wo` must be set.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"value_wo": schema.StringAttribute{
+				MarkdownDescription: "The value of the secret, never stored in state or plan. Requires Terraform 1.11 or later and `value_wo_version`.",
+				Optional:            true,
 				Sensitive:           true,
+				WriteOnly:           true,
+			},
+			"value_wo_version": schema.Int64Attribute{
+				MarkdownDescription: "Version of `value_wo`. As the value itself is not stored, it is only sent when the version changes, so bump it to rotate the secret.",
+				Optional:            true,
 			},
 			"is_protected": schema.BoolAttribute{
-				MarkdownDescription: "If true, the secret value cannot be retrieved via the API. Default: false",
+				MarkdownDescription: "If true, the secret value cannot be retrieved via the API. Bitrise does not allow removing the protection, so setting it back to false replaces the secret. Default: false",
 				Optional:            true,
 				Computed:            true,
 				Default:             booldefault.StaticBool(false),
@@ -125,24 +140,104 @@ func (r *AppSecretsResource) Schema(ctx context.Context, req resource.SchemaRequ
 	}
 }
 
+func (r *AppSecretsResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("value"),
+			path.MatchRoot("value_wo"),
+		),
+		resourcevalidator.RequiredTogether(
+			path.MatchRoot("value_wo"),
+			path.MatchRoot("value_wo_version"),
+		),
+	}
+}
+
+// ModifyPlan replaces a secret whose protection is removed, as Bitrise
+// rejects unprotecting a secret, plans the rewrite of a write-only value
+// changed outside of Terraform, and warns when a protected secret is exposed
+// to the pull requests of a public app.
+func (r *AppSecretsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan AppSecretsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state AppSecretsResourceModel
+	creating := req.State.Raw.IsNull()
+	if !creating {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if unprotectsSecret(state.IsProtected, plan.IsProtected) {
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root("is_protected"))
+		}
+
+		// A write-only value changed outside of Terraform is written again
+		// with the same version, an unknown id turns the plan into an update
+		if !plan.ValueWOVersion.IsNull() && plan.ValueWOVersion.Equal(state.ValueWOVersion) {
+			hash := loadSecretValueHash(ctx, req.Private, &resp.Diagnostics)
+			if hash != nil && hash.Rewrite {
+				resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("id"), types.StringUnknown())...)
+			}
+		}
+	}
+
+	if !plan.IsProtected.ValueBool() || !plan.IsExposedForPullRequests.ValueBool() {
+		return
+	}
+	// Only look the app up when the combination is new, not on every plan
+	if !creating && state.IsProtected.ValueBool() && state.IsExposedForPullRequests.ValueBool() {
+		return
+	}
+	if plan.AppSlug.IsUnknown() || r.client == nil {
+		return
+	}
+
+	app, err := r.client.GetApp(ctx, plan.AppSlug.ValueString())
+	if err != nil {
+		tflog.Warn(ctx, "Unable to read app, skipping pull request exposure check", map[string]interface{}{"error": err.Error()})
+		return
+	}
+
+	if app.IsPublic {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("is_exposed_for_pull_requests"),
+			"Protected Secret Exposed to Pull Requests",
+			fmt.Sprintf("App %s is public, so anyone can open a pull request, including from a fork, and its build can read the secret %s. "+
+				"Protection only hides the value from the API and the website, not from the builds.", plan.AppSlug.ValueString(), plan.Name.ValueString()),
+		)
+	}
+}
+
 func (r *AppSecretsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	if req.ProviderData == nil {
 		return
 	}
 
-	clientCreator, ok := req.ProviderData.(func(endpoint, token string) *http.Client)
+	client, ok := req.ProviderData.(*bitrise.Client)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected func(endpoint, token string) *http.Client, got: %T", req.ProviderData),
+			fmt.Sprintf("Expected *bitrise.Client, got: %T", req.ProviderData),
 		)
 		return
 	}
 
-	r.clientCreator = clientCreator
+	r.client = client
 }
 
 func (r *AppSecretsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.LogContext(ctx)
+
 	var data AppSecretsResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
@@ -154,68 +249,37 @@ func (r *AppSecretsResource) Create(ctx context.Context, req resource.CreateRequ
 		"name":     data.Name.ValueString(),
 	})
 
-	client := r.clientCreator(r.endpoint, r.token)
-	url := fmt.Sprintf("%s/v0.1/apps/%s/secrets", r.endpoint, data.AppSlug.ValueString())
+	value := data.Value.ValueString()
+	writeOnly := !data.ValueWOVersion.IsNull()
+	if writeOnly {
+		value = writeOnlySecretValue(ctx, req.Config, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
-	secretReq := SecretCreateRequest{
+	secretReq := bitrise.CreateSecretParams{
 		Name:                     data.Name.ValueString(),
-		Value:                    data.Value.ValueString(),
+		Value:                    value,
 		IsProtected:              data.IsProtected.ValueBool(),
 		IsExposedForPullRequests: data.IsExposedForPullRequests.ValueBool(),
 		ExpandInStepInputs:       data.ExpandInStepInputs.ValueBool(),
 	}
 
-	payloadJSON, err := json.Marshal(secretReq)
-	if err != nil {
-		resp.Diagnostics.AddError("Error marshaling request", err.Error())
-		return
-	}
-
-	tflog.Debug(ctx, "Sending POST request to create secret", map[string]interface{}{
-		"url": url,
-	})
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, strings.NewReader(string(payloadJSON)))
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating HTTP request", err.Error())
-		return
-	}
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	httpResp, err := client.Do(httpReq)
-	if err != nil {
-		resp.Diagnostics.AddError("Error sending HTTP request", err.Error())
-		return
-	}
-	defer httpResp.Body.Close()
-
-	responseBody, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		resp.Diagnostics.AddError("Error reading response body", err.Error())
-		return
-	}
-
-	if httpResp.StatusCode != http.StatusCreated {
-		tflog.Error(ctx, "Failed to create secret", map[string]interface{}{
-			"status": httpResp.Status,
-			"body":   string(responseBody),
-		})
-		resp.Diagnostics.AddError(
-			"API Error",
-			fmt.Sprintf("Failed to create secret: %s - %s", httpResp.Status, string(responseBody)),
-		)
-		return
-	}
-
-	var secretResp SecretResponse
-	if err := json.Unmarshal(responseBody, &secretResp); err != nil {
-		resp.Diagnostics.AddError("Error parsing response", err.Error())
+	if _, err := r.client.CreateSecret(ctx, data.AppSlug.ValueString(), secretReq); err != nil {
+		tflog.Error(ctx, "Failed to create secret", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to create secret: %s", err))
 		return
 	}
 
 	// Set the ID to a combination of app_slug and secret name for import/identification
 	data.ID = types.StringValue(fmt.Sprintf("%s/%s", data.AppSlug.ValueString(), data.Name.ValueString()))
 
+	recordSecretWrite(ctx, appSecretGetter(r.client, data.AppSlug.ValueString()), data.Name.ValueString(), resp.Private, resp.Private, &resp.Diagnostics)
+	if writeOnly {
+		saveSecretValueHash(ctx, resp.Private, value, &resp.Diagnostics)
+	}
+
 	tflog.Info(ctx, "Successfully created Bitrise app secret", map[string]interface{}{
 		"id": data.ID.ValueString(),
 	})
@@ -224,6 +288,8 @@ func (r *AppSecretsResource) Create(ctx context.Context, req resource.CreateRequ
 }
 
 func (r *AppSecretsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.LogContext(ctx)
+
 	var data AppSecretsResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
@@ -235,23 +301,8 @@ func (r *AppSecretsResource) Read(ctx context.Context, req resource.ReadRequest,
 		"name":     data.Name.ValueString(),
 	})
 
-	client := r.clientCreator(r.endpoint, r.token)
-	url := fmt.Sprintf("%s/v0.1/apps/%s/secrets/%s", r.endpoint, data.AppSlug.ValueString(), data.Name.ValueString())
-
-	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating HTTP request", err.Error())
-		return
-	}
-
-	httpResp, err := client.Do(httpReq)
-	if err != nil {
-		resp.Diagnostics.AddError("Error sending HTTP request", err.Error())
-		return
-	}
-	defer httpResp.Body.Close()
-
-	if httpResp.StatusCode == http.StatusNotFound {
+	secretResp, err := r.client.GetSecret(ctx, data.AppSlug.ValueString(), data.Name.ValueString())
+	if bitrise.IsNotFound(err) {
 		tflog.Info(ctx, "Secret not found, removing from state", map[string]interface{}{
 			"app_slug": data.AppSlug.ValueString(),
 			"name":     data.Name.ValueString(),
@@ -259,29 +310,33 @@ func (r *AppSecretsResource) Read(ctx context.Context, req resource.ReadRequest,
 		resp.State.RemoveResource(ctx)
 		return
 	}
-
-	responseBody, err := io.ReadAll(httpResp.Body)
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading response body", err.Error())
+		tflog.Error(ctx, "Failed to read secret", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to read secret: %s", err))
 		return
 	}
 
-	if httpResp.StatusCode != http.StatusOK {
-		tflog.Error(ctx, "Failed to read secret", map[string]interface{}{
-			"status": httpResp.Status,
-			"body":   string(responseBody),
-		})
-		resp.Diagnostics.AddError(
-			"API Error",
-			fmt.Sprintf("Failed to read secret: %s - %s", httpResp.Status, string(responseBody)),
-		)
-		return
+	// The hash of the last write-only value marks a write-only secret, even
+	// when value_wo_version is missing from state
+	hash := loadSecretValueHash(ctx, req.Private, &resp.Diagnostics)
+	writeOnly := hash != nil || !data.ValueWOVersion.IsNull()
+
+	// Clearing the value of a protected secret written outside of Terraform
+	// plans the configured value to be written again. A write-only value is
+	// marked for a rewrite instead, see ModifyPlan.
+	if refreshSecretWrites(ctx, secretResp, "app "+data.AppSlug.ValueString(), req.Private, resp.Private, &resp.Diagnostics) {
+		if writeOnly {
+			hash = requestSecretRewrite(ctx, resp.Private, hash, &resp.Diagnostics)
+		} else {
+			data.Value = types.StringNull()
+		}
 	}
 
-	var secretResp SecretResponse
-	if err := json.Unmarshal(responseBody, &secretResp); err != nil {
-		resp.Diagnostics.AddError("Error parsing response", err.Error())
-		return
+	// Readable write-only values are compared with the hash of the last write
+	if hash != nil && !hash.Rewrite && !secretResp.IsProtected && secretResp.Value != "" && !hash.matches(secretResp.Value) {
+		resp.Diagnostics.AddWarning("Secret Changed Outside Terraform",
+			fmt.Sprintf("The value of secret %s of app %s no longer matches the value Terraform last wrote. The next apply writes the configured value again.", data.Name.ValueString(), data.AppSlug.ValueString()))
+		requestSecretRewrite(ctx, resp.Private, hash, &resp.Diagnostics)
 	}
 
 	// Update state with values from API
@@ -289,9 +344,12 @@ func (r *AppSecretsResource) Read(ctx context.Context, req resource.ReadRequest,
 	data.IsProtected = types.BoolValue(secretResp.IsProtected)
 	data.IsExposedForPullRequests = types.BoolValue(secretResp.IsExposedForPullRequests)
 	data.ExpandInStepInputs = types.BoolValue(secretResp.ExpandInStepInputs)
-	
-	// Only update value if it's not protected and returned by API
-	if !secretResp.IsProtected && secretResp.Value != "" {
+
+	// Only update value if it's not protected and returned by API, and never
+	// store a write-only value
+	if writeOnly {
+		data.Value = types.StringNull()
+	} else if !secretResp.IsProtected && secretResp.Value != "" {
 		data.Value = types.StringValue(secretResp.Value)
 	}
 
@@ -299,8 +357,11 @@ func (r *AppSecretsResource) Read(ctx context.Context, req resource.ReadRequest,
 }
 
 func (r *AppSecretsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data AppSecretsResourceModel
+	ctx = r.client.LogContext(ctx)
+
+	var data, state AppSecretsResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -310,68 +371,57 @@ func (r *AppSecretsResource) Update(ctx context.Context, req resource.UpdateRequ
 		"name":     data.Name.ValueString(),
 	})
 
-	client := r.clientCreator(r.endpoint, r.token)
-	url := fmt.Sprintf("%s/v0.1/apps/%s/secrets/%s", r.endpoint, data.AppSlug.ValueString(), data.Name.ValueString())
-
 	// Build update request with only the fields that should be updated
 	isProtected := data.IsProtected.ValueBool()
 	isExposed := data.IsExposedForPullRequests.ValueBool()
 	expandInputs := data.ExpandInStepInputs.ValueBool()
 
-	secretReq := SecretUpdateRequest{
-		Value:                    data.Value.ValueString(),
+	// A write-only value is only sent when its version changes, an empty value
+	// leaves the secret value untouched
+	value := data.Value.ValueString()
+	writeOnly := !data.ValueWOVersion.IsNull()
+	hash := loadSecretValueHash(ctx, req.Private, &resp.Diagnostics)
+	if writeOnly {
+		value = ""
+		if !data.ValueWOVersion.Equal(state.ValueWOVersion) || hash != nil && hash.Rewrite {
+			value = writeOnlySecretValue(ctx, req.Config, &resp.Diagnostics)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+	}
+
+	secretReq := bitrise.UpdateSecretParams{
+		Value:                    value,
 		IsProtected:              &isProtected,
 		IsExposedForPullRequests: &isExposed,
 		ExpandInStepInputs:       &expandInputs,
 	}
 
-	payloadJSON, err := json.Marshal(secretReq)
-	if err != nil {
-		resp.Diagnostics.AddError("Error marshaling request", err.Error())
-		return
-	}
-
-	tflog.Debug(ctx, "Sending PATCH request to update secret", map[string]interface{}{
-		"url": url,
-	})
-
-	httpReq, err := http.NewRequestWithContext(ctx, "PATCH", url, strings.NewReader(string(payloadJSON)))
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating HTTP request", err.Error())
-		return
-	}
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	httpResp, err := client.Do(httpReq)
-	if err != nil {
-		resp.Diagnostics.AddError("Error sending HTTP request", err.Error())
+	if err := r.client.UpdateSecret(ctx, data.AppSlug.ValueString(), data.Name.ValueString(), secretReq); err != nil {
+		tflog.Error(ctx, "Failed to update secret", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to update secret: %s", err))
 		return
 	}
-	defer httpResp.Body.Close()
 
-	responseBody, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		resp.Diagnostics.AddError("Error reading response body", err.Error())
-		return
+	recordSecretWrite(ctx, appSecretGetter(r.client, data.AppSlug.ValueString()), data.Name.ValueString(), req.Private, resp.Private, &resp.Diagnostics)
+	if writeOnly && value != "" {
+		saveSecretValueHash(ctx, resp.Private, value, &resp.Diagnostics)
+	} else if !writeOnly && hash != nil {
+		// Switched to value, the secret is no longer write-only
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, secretValueHashKey, nil)...)
 	}
 
-	if httpResp.StatusCode != http.StatusOK {
-		tflog.Error(ctx, "Failed to update secret", map[string]interface{}{
-			"status": httpResp.Status,
-			"body":   string(responseBody),
-		})
-		resp.Diagnostics.AddError(
-			"API Error",
-			fmt.Sprintf("Failed to update secret: %s - %s", httpResp.Status, string(responseBody)),
-		)
-		return
-	}
+	// ModifyPlan leaves id unknown to plan a rewrite
+	data.ID = types.StringValue(fmt.Sprintf("%s/%s", data.AppSlug.ValueString(), data.Name.ValueString()))
 
 	tflog.Info(ctx, "Successfully updated Bitrise app secret")
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *AppSecretsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.client.LogContext(ctx)
+
 	var data AppSecretsResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
@@ -383,38 +433,15 @@ func (r *AppSecretsResource) Delete(ctx context.Context, req resource.DeleteRequ
 		"name":     data.Name.ValueString(),
 	})
 
-	client := r.clientCreator(r.endpoint, r.token)
-	url := fmt.Sprintf("%s/v0.1/apps/%s/secrets/%s", r.endpoint, data.AppSlug.ValueString(), data.Name.ValueString())
-
-	httpReq, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating HTTP request", err.Error())
-		return
-	}
-
-	httpResp, err := client.Do(httpReq)
-	if err != nil {
-		resp.Diagnostics.AddError("Error sending HTTP request", err.Error())
-		return
-	}
-	defer httpResp.Body.Close()
-
+	err := r.client.DeleteSecret(ctx, data.AppSlug.ValueString(), data.Name.ValueString())
 	// 404 means already deleted, which is fine
-	if httpResp.StatusCode == http.StatusNotFound {
+	if bitrise.IsNotFound(err) {
 		tflog.Info(ctx, "Secret already deleted")
 		return
 	}
-
-	if httpResp.StatusCode != http.StatusNoContent {
-		responseBody, _ := io.ReadAll(httpResp.Body)
-		tflog.Error(ctx, "Failed to delete secret", map[string]interface{}{
-			"status": httpResp.Status,
-			"body":   string(responseBody),
-		})
-		resp.Diagnostics.AddError(
-			"API Error",
-			fmt.Sprintf("Failed to delete secret: %s - %s", httpResp.Status, string(responseBody)),
-		)
+	if err != nil {
+		tflog.Error(ctx, "Failed to delete secret", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to delete secret: %s", err))
 		return
 	}
 
@@ -435,7 +462,82 @@ func (r *AppSecretsResource) ImportState(ctx context.Context, req resource.Impor
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("app_slug"), parts[0])...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), parts[1])...)
 	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
-	
+
 	// Note: The value won't be imported, user needs to set it manually after import
 	// or if the secret is not protected, it will be fetched during the first read
 }
+
+// writeOnlySecretValue returns value_wo, which is only available in the
+// configuration.
+func writeOnlySecretValue(ctx context.Context, config tfsdk.Config, diags *diag.Diagnostics) string {
+	var value types.String
+	diags.Append(config.GetAttribute(ctx, path.Root("value_wo"), &value)...)
+	return value.ValueString()
+}
+
+// secretValueHash is a salted hash of a write-only secret value. It detects
+// changes of readable secrets without storing their value, and its presence
+// marks the secret as write-only. Rewrite is set when the secret changed
+// outside of Terraform and the next apply has to write the value again.
+type secretValueHash struct {
+	Salt    []byte `json:"salt"`
+	Hash    []byte `json:"hash"`
+	Rewrite bool   `json:"rewrite,omitempty"`
+}
+
+func hashSecretValue(salt []byte, value string) []byte {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+func (h *secretValueHash) matches(value string) bool {
+	return hmac.Equal(h.Hash, hashSecretValue(h.Salt, value))
+}
+
+func saveSecretValueHash(ctx context.Context, private privateState, value string, diags *diag.Diagnostics) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		diags.AddError("Invalid Private State", fmt.Sprintf("Unable to generate a salt for the secret value hash: %s", err))
+		return
+	}
+
+	storeSecretValueHash(ctx, private, &secretValueHash{Salt: salt, Hash: hashSecretValue(salt, value)}, diags)
+}
+
+// requestSecretRewrite marks a write-only value to be written again by the
+// next apply. Without a hash, only the marker is stored.
+func requestSecretRewrite(ctx context.Context, private privateState, hash *secretValueHash, diags *diag.Diagnostics) *secretValueHash {
+	if hash == nil {
+		hash = &secretValueHash{}
+	}
+	hash.Rewrite = true
+	storeSecretValueHash(ctx, private, hash, diags)
+	return hash
+}
+
+func storeSecretValueHash(ctx context.Context, private privateState, hash *secretValueHash, diags *diag.Diagnostics) {
+	raw, err := json.Marshal(hash)
+	if err != nil {
+		diags.AddError("Invalid Private State", fmt.Sprintf("Unable to encode the secret value hash: %s", err))
+		return
+	}
+
+	diags.Append(private.SetKey(ctx, secretValueHashKey, raw)...)
+}
+
+func loadSecretValueHash(ctx context.Context, private privateState, diags *diag.Diagnostics) *secretValueHash {
+	raw, d := private.GetKey(ctx, secretValueHashKey)
+	diags.Append(d...)
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var hash secretValueHash
+	if err := json.Unmarshal(raw, &hash); err != nil {
+		diags.AddError("Invalid Private State", fmt.Sprintf("Unable to parse the secret value hash: %s", err))
+		return nil
+	}
+
+	return &hash
+}
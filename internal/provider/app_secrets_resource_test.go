@@ -0,0 +1,311 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"terraform-provider-bitrise/internal/bitrise"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// secretAPI serves a single app secret, applying PATCH requests to it and
+// bumping its update timestamp.
+type secretAPI struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	secret  bitrise.Secret
+	updates []bitrise.UpdateSecretParams
+}
+
+func newSecretAPI(t *testing.T, secret bitrise.Secret) *secretAPI {
+	t.Helper()
+
+	api := &secretAPI{secret: secret}
+	api.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+
+		if r.URL.Path != "/v0.1/apps/app-slug/secrets/"+api.secret.Name {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPatch:
+			var params bitrise.UpdateSecretParams
+			body, _ := io.ReadAll(r.Body)
+			if err := json.Unmarshal(body, &params); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			api.updates = append(api.updates, params)
+			api.secret.UpdatedAt = "2025-01-04T12:00:00Z"
+			if !api.secret.IsProtected {
+				api.secret.Value = params.Value
+			}
+		default:
+			http.Error(w, "unexpected method", http.StatusMethodNotAllowed)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(api.secret)
+	}))
+	t.Cleanup(api.Close)
+
+	return api
+}
+
+func newAppSecretsTestResource(t *testing.T, api *secretAPI) (*AppSecretsResource, resource.SchemaResponse) {
+	t.Helper()
+
+	r := &AppSecretsResource{client: bitrise.NewClient(api.URL, api.Client())}
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(context.Background(), resource.SchemaRequest{}, &schemaResp)
+	if schemaResp.Diagnostics.HasError() {
+		t.Fatalf("Schema() diagnostics = %v", schemaResp.Diagnostics)
+	}
+
+	return r, schemaResp
+}
+
+// appSecretsTestData returns the raw value of model, to be used as state,
+// plan or configuration.
+func appSecretsTestData(t *testing.T, schemaResp resource.SchemaResponse, model AppSecretsResourceModel) tftypes.Value {
+	t.Helper()
+
+	ctx := context.Background()
+	state := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+	}
+	if diags := state.Set(ctx, &model); diags.HasError() {
+		t.Fatalf("Set() diagnostics = %v", diags)
+	}
+
+	return state.Raw
+}
+
+func writeOnlyAppSecretModel() AppSecretsResourceModel {
+	return AppSecretsResourceModel{
+		AppSlug:                  types.StringValue("app-slug"),
+		Name:                     types.StringValue("API_KEY"),
+		Value:                    types.StringNull(),
+		ValueWO:                  types.StringNull(),
+		ValueWOVersion:           types.Int64Value(1),
+		IsProtected:              types.BoolValue(true),
+		IsExposedForPullRequests: types.BoolValue(false),
+		ExpandInStepInputs:       types.BoolValue(true),
+		ID:                       types.StringValue("app-slug/API_KEY"),
+	}
+}
+
+func TestAppSecretsReadRewriteMarker(t *testing.T) {
+	ctx := context.Background()
+	api := newSecretAPI(t, bitrise.Secret{Name: "API_KEY", IsProtected: true, ExpandInStepInputs: true, UpdatedAt: "2025-01-03T08:30:00Z"})
+	r, schemaResp := newAppSecretsTestResource(t, api)
+
+	private := fakePrivate{secretWritesKey: mustJSON(t, secretWrites{"API_KEY": "2025-01-02T10:00:00Z"})}
+	var diags diag.Diagnostics
+	saveSecretValueHash(ctx, private, "old-value", &diags)
+	if diags.HasError() {
+		t.Fatalf("saveSecretValueHash() diagnostics = %v", diags)
+	}
+
+	// The first Read detects the write outside of Terraform, later ones keep
+	// the marker as the recorded timestamp is only updated by a write
+	for i := 1; i <= 2; i++ {
+		req := resource.ReadRequest{State: tfsdk.State{Schema: schemaResp.Schema, Raw: appSecretsTestData(t, schemaResp, writeOnlyAppSecretModel())}}
+		resp := resource.ReadResponse{State: req.State}
+		initPrivate(t, &req.Private, private)
+		initPrivate(t, &resp.Private, private)
+
+		r.Read(ctx, req, &resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("Read() #%d diagnostics = %v", i, resp.Diagnostics)
+		}
+		if resp.Diagnostics.WarningsCount() != 1 {
+			t.Errorf("Read() #%d warnings = %v, want the drift warning", i, resp.Diagnostics)
+		}
+
+		got := loadSecretValueHash(ctx, resp.Private, &resp.Diagnostics)
+		if got == nil || !got.Rewrite || !got.matches("old-value") {
+			t.Fatalf("Read() #%d value hash = %+v, want the hash of the last write with the rewrite marker", i, got)
+		}
+
+		var data AppSecretsResourceModel
+		resp.Diagnostics.Append(resp.State.Get(ctx, &data)...)
+		if !data.Value.IsNull() {
+			t.Errorf("Read() #%d stored value %q of a write-only secret", i, data.Value.ValueString())
+		}
+
+		private[secretValueHashKey], _ = resp.Private.GetKey(ctx, secretValueHashKey)
+		private[secretWritesKey], _ = resp.Private.GetKey(ctx, secretWritesKey)
+	}
+}
+
+func TestAppSecretsUpdateClearsRewriteMarker(t *testing.T) {
+	ctx := context.Background()
+	api := newSecretAPI(t, bitrise.Secret{Name: "API_KEY", IsProtected: true, ExpandInStepInputs: true, UpdatedAt: "2025-01-03T08:30:00Z"})
+	r, schemaResp := newAppSecretsTestResource(t, api)
+
+	private := map[string][]byte{
+		secretWritesKey:    mustJSON(t, secretWrites{"API_KEY": "2025-01-02T10:00:00Z"}),
+		secretValueHashKey: mustJSON(t, &secretValueHash{Rewrite: true}),
+	}
+
+	// ModifyPlan leaves id unknown, the version is unchanged
+	plan := writeOnlyAppSecretModel()
+	plan.ID = types.StringUnknown()
+	config := writeOnlyAppSecretModel()
+	config.ID = types.StringNull()
+	config.ValueWO = types.StringValue("new-value")
+
+	req := resource.UpdateRequest{
+		State:  tfsdk.State{Schema: schemaResp.Schema, Raw: appSecretsTestData(t, schemaResp, writeOnlyAppSecretModel())},
+		Plan:   tfsdk.Plan{Schema: schemaResp.Schema, Raw: appSecretsTestData(t, schemaResp, plan)},
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: appSecretsTestData(t, schemaResp, config)},
+	}
+	resp := resource.UpdateResponse{State: req.State}
+	initPrivate(t, &req.Private, private)
+	initPrivate(t, &resp.Private, private)
+
+	r.Update(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("Update() diagnostics = %v", resp.Diagnostics)
+	}
+
+	if len(api.updates) != 1 || api.updates[0].Value != "new-value" {
+		t.Errorf("Update() sent %+v, want the write-only value written again", api.updates)
+	}
+
+	hash := loadSecretValueHash(ctx, resp.Private, &resp.Diagnostics)
+	if hash == nil || hash.Rewrite || !hash.matches("new-value") {
+		t.Errorf("Update() value hash = %+v, want the hash of the written value without the rewrite marker", hash)
+	}
+	if writes := loadSecretWrites(ctx, resp.Private, &resp.Diagnostics); writes["API_KEY"] != "2025-01-04T12:00:00Z" {
+		t.Errorf("Update() recorded writes = %v, want the timestamp of the write", writes)
+	}
+
+	var data AppSecretsResourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &data)...)
+	if data.ID.ValueString() != "app-slug/API_KEY" || !data.Value.IsNull() {
+		t.Errorf("Update() state id = %s, value = %s, want app-slug/API_KEY and no value", data.ID, data.Value)
+	}
+}
+
+func TestSecretNamePattern(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"API_KEY", true},
+		{"_PRIVATE", true},
+		{"key2", true},
+		{"K", true},
+		{"2FA_KEY", false},
+		{"API-KEY", false},
+		{"API KEY", false},
+		{"$API_KEY", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := secretNamePattern.MatchString(tt.name); got != tt.want {
+				t.Errorf("secretNamePattern.MatchString(%q) = %t, want %t", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnprotectsSecret(t *testing.T) {
+	tests := []struct {
+		name  string
+		state types.Bool
+		plan  types.Bool
+		want  bool
+	}{
+		{"protected to unprotected", types.BoolValue(true), types.BoolValue(false), true},
+		{"unprotected to protected", types.BoolValue(false), types.BoolValue(true), false},
+		{"stays protected", types.BoolValue(true), types.BoolValue(true), false},
+		{"stays unprotected", types.BoolValue(false), types.BoolValue(false), false},
+		{"protected to unknown", types.BoolValue(true), types.BoolUnknown(), false},
+		{"no state", types.BoolNull(), types.BoolValue(false), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unprotectsSecret(tt.state, tt.plan); got != tt.want {
+				t.Errorf("unprotectsSecret(%s, %s) = %t, want %t", tt.state, tt.plan, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppSecretsModifyPlanProtection(t *testing.T) {
+	tests := []struct {
+		name            string
+		stateProtected  bool
+		planProtected   bool
+		wantReplacement bool
+	}{
+		{"protected to unprotected", true, false, true},
+		{"unprotected to protected", false, true, false},
+		{"stays protected", true, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			r := &AppSecretsResource{}
+
+			var schemaResp resource.SchemaResponse
+			r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+			state := AppSecretsResourceModel{
+				AppSlug:                  types.StringValue("app-slug"),
+				Name:                     types.StringValue("API_KEY"),
+				Value:                    types.StringValue("value"),
+				ValueWO:                  types.StringNull(),
+				ValueWOVersion:           types.Int64Null(),
+				IsProtected:              types.BoolValue(tt.stateProtected),
+				IsExposedForPullRequests: types.BoolValue(false),
+				ExpandInStepInputs:       types.BoolValue(true),
+				ID:                       types.StringValue("app-slug/API_KEY"),
+			}
+			plan := state
+			plan.IsProtected = types.BoolValue(tt.planProtected)
+
+			req := resource.ModifyPlanRequest{
+				State:  tfsdk.State{Schema: schemaResp.Schema, Raw: appSecretsTestData(t, schemaResp, state)},
+				Plan:   tfsdk.Plan{Schema: schemaResp.Schema, Raw: appSecretsTestData(t, schemaResp, plan)},
+				Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: appSecretsTestData(t, schemaResp, plan)},
+			}
+			resp := resource.ModifyPlanResponse{Plan: req.Plan}
+			initPrivate(t, &req.Private, nil)
+			initPrivate(t, &resp.Private, nil)
+
+			r.ModifyPlan(ctx, req, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("ModifyPlan() diagnostics = %v", resp.Diagnostics)
+			}
+
+			if got := len(resp.RequiresReplace) > 0; got != tt.wantReplacement {
+				t.Errorf("ModifyPlan() RequiresReplace = %v, want replacement %t", resp.RequiresReplace, tt.wantReplacement)
+			}
+		})
+	}
+}
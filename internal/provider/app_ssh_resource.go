@@ -2,45 +2,55 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-	"net/http/httputil"
-	"os"
-	"strings"
 
+	"terraform-provider-bitrise/internal/bitrise"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/crypto/ssh"
 )
 
-type Payload struct {
-	AuthSSHPrivateKey                string `json:"auth_ssh_private_key"`
-	AuthSSHPublicKey                 string `json:"auth_ssh_public_key"`
-	IsRegisterKeyIntoProviderService bool   `json:"is_register_key_into_provider_service"`
-}
+var _ resource.Resource = &AppSSHResource{}
+var _ resource.ResourceWithImportState = &AppSSHResource{}
+var _ resource.ResourceWithValidateConfig = &AppSSHResource{}
+var _ resource.ResourceWithConfigValidators = &AppSSHResource{}
+var _ resource.ResourceWithModifyPlan = &AppSSHResource{}
 
 type AppSSHResource struct {
-	clientCreator func(endpoint, token string) *http.Client
-	endpoint      string
-	token         string
+	client *bitrise.Client
 }
 
-func NewAppSSHResource(clientCreator func(endpoint, token string) *http.Client, endpoint, token string) *AppSSHResource {
-	return &AppSSHResource{
-		clientCreator: clientCreator,
-		endpoint:      endpoint,
-		token:         token,
-	}
+func NewAppSSHResource() resource.Resource {
+	return &AppSSHResource{}
 }
 
 type AppSSHResourceModel struct {
-	AppSlug                          string `tfsdk:"app_slug"`
-	AuthSSHPrivateKey                string `tfsdk:"auth_ssh_private_key"`
-	AuthSSHPublicKey                 string `tfsdk:"auth_ssh_public_key"`
-	IsRegisterKeyIntoProviderService bool   `tfsdk:"is_register_key_into_provider_service"`
+	AppSlug                          types.String `tfsdk:"app_slug"`
+	AuthSSHPrivateKey                types.String `tfsdk:"auth_ssh_private_key"`
+	AuthSSHPublicKey                 types.String `tfsdk:"auth_ssh_public_key"`
+	IsRegisterKeyIntoProviderService types.Bool   `tfsdk:"is_register_key_into_provider_service"`
+	Fingerprint                      types.String `tfsdk:"fingerprint"`
+	GenerateKey                      types.Object `tfsdk:"generate_key"`
+	RotationTrigger                  types.String `tfsdk:"rotation_trigger"`
+}
+
+// AppSSHGenerateKeyModel describes the keypair generated by the resource when
+// the keys are not provided.
+type AppSSHGenerateKeyModel struct {
+	Algorithm types.String `tfsdk:"algorithm"`
+	RSABits   types.Int64  `tfsdk:"rsa_bits"`
 }
 
 func (r *AppSSHResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -56,151 +66,196 @@ func (r *AppSSHResource) Schema(ctx context.Context, req resource.SchemaRequest,
 				Required:            true,
 			},
 			"auth_ssh_private_key": schema.StringAttribute{
-				MarkdownDescription: "Private SSH key for authentication",
-				Required:            true,
+				MarkdownDescription: "Unencrypted private SSH key for authentication, in PEM or OpenSSH format. Computed when `generate_key` is set.",
+				Optional:            true,
+				Computed:            true,
+				Sensitive:           true,
 			},
 			"auth_ssh_public_key": schema.StringAttribute{
-				MarkdownDescription: "Public SSH key for authentication",
-				Required:            true,
+				MarkdownDescription: "Public SSH key for authentication, in authorized_keys format. Must belong to `auth_ssh_private_key`. Computed when `generate_key` is set.",
+				Optional:            true,
+				Computed:            true,
 			},
 			"is_register_key_into_provider_service": schema.BoolAttribute{
 				MarkdownDescription: "Whether to register the public key into the provider service",
 				Optional:            true,
 			},
+			"fingerprint": schema.StringAttribute{
+				MarkdownDescription: "SHA256 fingerprint of the registered public key",
+				Computed:            true,
+			},
+			"generate_key": schema.SingleNestedAttribute{
+				MarkdownDescription: "Generate the keypair instead of providing `auth_ssh_private_key` and `auth_ssh_public_key`. Changing it generates a new keypair.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"algorithm": schema.StringAttribute{
+						MarkdownDescription: "Key algorithm, `ed25519` or `rsa`. Default: `ed25519`",
+						Optional:            true,
+						Computed:            true,
+						Default:             stringdefault.StaticString(sshKeyAlgorithmED25519),
+						Validators: []validator.String{
+							stringvalidator.OneOf(sshKeyAlgorithmED25519, sshKeyAlgorithmRSA),
+						},
+					},
+					"rsa_bits": schema.Int64Attribute{
+						MarkdownDescription: "Size of the RSA key in bits, ignored for ed25519. Default: `4096`",
+						Optional:            true,
+						Computed:            true,
+						Default:             int64default.StaticInt64(defaultSSHKeyRSABits),
+						Validators: []validator.Int64{
+							int64validator.AtLeast(minSSHKeyRSABits),
+						},
+					},
+				},
+			},
+			"rotation_trigger": schema.StringAttribute{
+				MarkdownDescription: "Arbitrary value that generates and registers a new keypair whenever it changes. Only used with `generate_key`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("generate_key")),
+				},
+			},
 		},
 	}
 }
 
-func (r *AppSSHResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
-	if req.ProviderData == nil {
-		tflog.Debug(ctx, "MODULEDEBUG: Provider data is missing")
-		return
+func (r *AppSSHResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("auth_ssh_private_key"),
+			path.MatchRoot("generate_key"),
+		),
+		resourcevalidator.RequiredTogether(
+			path.MatchRoot("auth_ssh_private_key"),
+			path.MatchRoot("auth_ssh_public_key"),
+		),
 	}
-	clientCreator, ok := req.ProviderData.(func(endpoint, token string) *http.Client)
-
-	if !ok {
-		resp.Diagnostics.AddError(
-			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
-		)
-		return
-	}
-
-	r.clientCreator = clientCreator
-	tflog.Debug(ctx, "MODULEDEBUG: Provider configuration successful")
 }
 
-func (r *AppSSHResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	// Initialize data to store API response
+func (r *AppSSHResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
 	var data AppSSHResourceModel
 
-	// Populate data from Terraform plan
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 
-	// Check for errors in diagnostics and return if found
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	tflog.Debug(ctx, "MODULEDEBUG: Starting AppSSHResource Create")
-
-	// Create an HTTP client using the client creator from the provider
-	client := r.clientCreator(r.endpoint, r.token)
-
-	// Construct the URL for creating the request
-	appSlug := data.AppSlug
-	completeURL := fmt.Sprintf("%s/v0.1/apps/%s/register-ssh-key", r.endpoint, appSlug)
+	// Keys coming from other resources are only known at apply time
+	if data.AuthSSHPrivateKey.IsUnknown() || data.AuthSSHPublicKey.IsUnknown() ||
+		data.AuthSSHPrivateKey.IsNull() || data.AuthSSHPublicKey.IsNull() {
+		return
+	}
 
-	filePath := "testtfkey"
-	fileContent := []byte(data.AuthSSHPrivateKey)
+	if _, err := parseSSHKeypair(data.AuthSSHPrivateKey.ValueString(), data.AuthSSHPublicKey.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("auth_ssh_private_key"), "Invalid SSH Keypair", err.Error())
+	}
+}
 
-	err := os.WriteFile(filePath, fileContent, 0644)
-	if err != nil {
-		tflog.Error(ctx, "Error writing to file", map[string]interface{}{"error": err.Error()})
+func (r *AppSSHResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
 		return
 	}
 
-	// Construct the payload data using the provided variables
-	privateKeyPath := "testtfkey"
-	privateKeyBytes, err := os.ReadFile(privateKeyPath)
-	if err != nil {
-		tflog.Error(ctx, "Error reading private key file", map[string]interface{}{"error": err.Error()})
+	var plan AppSSHResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	privateKey := string(privateKeyBytes)
 
-	tflog.Debug(ctx, "MODULEDEBUG: Variable content written to file", map[string]interface{}{"file": filePath})
+	if !plan.GenerateKey.IsNull() {
+		r.planGeneratedKey(ctx, req, resp, plan)
+		return
+	}
 
-	payload := Payload{
-		AuthSSHPrivateKey:                privateKey,
-		AuthSSHPublicKey:                 data.AuthSSHPublicKey,
-		IsRegisterKeyIntoProviderService: data.IsRegisterKeyIntoProviderService,
+	if plan.AuthSSHPublicKey.IsUnknown() || plan.AuthSSHPublicKey.IsNull() {
+		return
 	}
 
-	// Marshal the payload struct into a JSON string
-	payloadJSON, err := json.Marshal(payload)
+	// The fingerprint only depends on the public key, so it can be known at plan time
+	pub, err := parseSSHPublicKey(plan.AuthSSHPublicKey.ValueString())
 	if err != nil {
-		tflog.Error(ctx, "Error marshaling JSON payload", map[string]interface{}{"error": err.Error()})
-		handleRequestError(err, resp)
+		resp.Diagnostics.AddAttributeError(path.Root("auth_ssh_public_key"), "Invalid SSH Public Key", err.Error())
 		return
 	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("fingerprint"), ssh.FingerprintSHA256(pub))...)
+}
 
-	tflog.Debug(ctx, "MODULEDEBUG: SSH key payload prepared", map[string]interface{}{"payload_json": string(payloadJSON)})
+// planGeneratedKey keeps the generated keypair from state, or plans a new one
+// on creation, when the generation settings change or when rotation_trigger
+// changes.
+func (r *AppSSHResource) planGeneratedKey(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, plan AppSSHResourceModel) {
+	regenerate := req.State.Raw.IsNull()
+
+	var state AppSSHResourceModel
+	if !regenerate {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		regenerate = state.AuthSSHPrivateKey.IsNull() ||
+			!plan.GenerateKey.Equal(state.GenerateKey) ||
+			!plan.RotationTrigger.Equal(state.RotationTrigger)
+	}
 
-	// Create an HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", completeURL, strings.NewReader(string(payloadJSON)))
-	if err != nil {
-		tflog.Error(ctx, "Error creating HTTP request", map[string]interface{}{"error": err.Error()})
-		handleRequestError(err, resp)
-		return
+	if regenerate {
+		plan.AuthSSHPrivateKey = types.StringUnknown()
+		plan.AuthSSHPublicKey = types.StringUnknown()
+		plan.Fingerprint = types.StringUnknown()
+	} else {
+		plan.AuthSSHPrivateKey = state.AuthSSHPrivateKey
+		plan.AuthSSHPublicKey = state.AuthSSHPublicKey
+		plan.Fingerprint = state.Fingerprint
 	}
-	// Set headers
-	httpReq.Header.Set("Content-Type", "application/json")
 
-	// Dump the HTTP request details
-	dump, _ := httputil.DumpRequest(httpReq, true)
-	tflog.Debug(ctx, "MODULEDEBUG: HTTP Request", map[string]interface{}{"request": string(dump)})
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
 
-	// Send the HTTP request
-	httpResp, err := client.Do(httpReq)
-	if err != nil {
-		tflog.Error(ctx, "Error sending HTTP request", map[string]interface{}{"error": err.Error()})
-		handleRequestError(err, resp)
+func (r *AppSSHResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		tflog.Debug(ctx, "MODULEDEBUG: Provider data is missing")
 		return
 	}
-	defer httpResp.Body.Close()
+	client, ok := req.ProviderData.(*bitrise.Client)
 
-	// Read the response body
-	responseBody, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		tflog.Error(ctx, "Error reading response body", map[string]interface{}{"error": err.Error()})
-		handleRequestError(err, resp)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *bitrise.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+	tflog.Debug(ctx, "MODULEDEBUG: Provider configuration successful")
+}
+
+func (r *AppSSHResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.LogContext(ctx)
+
+	// Initialize data to store API response
+	var data AppSSHResourceModel
+
+	// Populate data from Terraform plan
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	// Check for errors in diagnostics and return if found
+	if resp.Diagnostics.HasError() {
 		return
 	}
-	tflog.Debug(ctx, "MODULEDEBUG: Response body", map[string]interface{}{"body": string(responseBody)})
 
-	// Debugging: Print response status and headers
-	printResponseInfo(httpResp)
+	tflog.Debug(ctx, "MODULEDEBUG: Starting AppSSHResource Create")
 
-	if httpResp.StatusCode != http.StatusOK {
-		tflog.Error(ctx, "Request did not succeed", map[string]interface{}{
-			"status": httpResp.Status,
-			"headers": httpResp.Header,
-		})
-		resp.Diagnostics.AddError("API Request Error", fmt.Sprintf("Request did not succeed: %s", httpResp.Status))
+	if !r.registerKey(ctx, &data, &resp.Diagnostics) {
 		return
 	}
 
 	tflog.Info(ctx, "SSH key registration completed successfully")
 
 	// Update resource state with populated data
-	resp.State.Set(ctx, &data)
-	// Delete the tfkey file
-        err = os.Remove(filePath)
-        if err != nil {
-	  tflog.Error(ctx, "Error deleting tfkey file", map[string]interface{}{"error": err.Error()})
-	  // Handle the error if needed
-        }
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *AppSSHResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -218,6 +273,8 @@ func (r *AppSSHResource) Delete(ctx context.Context, req resource.DeleteRequest,
 }
 
 func (r *AppSSHResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.LogContext(ctx)
+
 	var data AppSSHResourceModel
 
 	// Read Terraform prior state data into the model
@@ -229,63 +286,36 @@ func (r *AppSSHResource) Read(ctx context.Context, req resource.ReadRequest, res
 
 	tflog.Debug(ctx, "MODULEDEBUG: Starting AppSSHResource Read")
 
-	// Create an HTTP client using the client creator from the provider
-	client := r.clientCreator(r.endpoint, r.token)
-
 	// Get the app slug from state
-	appSlug := data.AppSlug
+	appSlug := data.AppSlug.ValueString()
 	if appSlug == "" {
 		tflog.Warn(ctx, "AppSlug is empty, skipping Read")
-		resp.State.Set(ctx, &data)
-		return
-	}
-
-	// Construct the URL to get the app details
-	completeURL := fmt.Sprintf("%s/v0.1/apps/%s", r.endpoint, appSlug)
-
-	tflog.Debug(ctx, "MODULEDEBUG: Fetching app details for SSH resource", map[string]interface{}{"url": completeURL})
-
-	// Create an HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "GET", completeURL, nil)
-	if err != nil {
-		tflog.Error(ctx, "Error creating HTTP request", map[string]interface{}{"error": err.Error()})
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create request: %s", err))
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 		return
 	}
 
-	// Send the HTTP request
-	httpResp, err := client.Do(httpReq)
-	if err != nil {
-		tflog.Error(ctx, "Error sending HTTP request", map[string]interface{}{"error": err.Error()})
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read app: %s", err))
-		return
-	}
-	defer httpResp.Body.Close()
-
+	_, err := r.client.GetApp(ctx, appSlug)
 	// If the app was deleted (404), remove it from state
-	if httpResp.StatusCode == http.StatusNotFound {
+	if bitrise.IsNotFound(err) {
 		tflog.Info(ctx, "App not found, removing SSH resource from state", map[string]interface{}{"app_slug": appSlug})
 		resp.State.RemoveResource(ctx)
 		return
 	}
-
-	if httpResp.StatusCode != http.StatusOK {
-		responseBody, _ := io.ReadAll(httpResp.Body)
-		tflog.Error(ctx, "Request did not succeed", map[string]interface{}{
-			"status": httpResp.Status,
-			"body":   string(responseBody),
-		})
-		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Unable to read app, got status: %s", httpResp.Status))
+	if err != nil {
+		tflog.Error(ctx, "Request did not succeed", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Unable to read app: %s", err))
 		return
 	}
 
 	tflog.Debug(ctx, "MODULEDEBUG: App still exists, keeping SSH resource in state")
 
 	// Save updated data into Terraform state
-	resp.State.Set(ctx, &data)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *AppSSHResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.client.LogContext(ctx)
+
 	var data AppSSHResourceModel
 
 	// Read Terraform plan data into the model
@@ -297,91 +327,72 @@ func (r *AppSSHResource) Update(ctx context.Context, req resource.UpdateRequest,
 
 	tflog.Debug(ctx, "MODULEDEBUG: Starting AppSSHResource Update")
 
-	// Create an HTTP client using the client creator from the provider
-	client := r.clientCreator(r.endpoint, r.token)
+	if !r.registerKey(ctx, &data, &resp.Diagnostics) {
+		return
+	}
 
-	// Construct the URL for updating the request
-	appSlug := data.AppSlug
-	completeURL := fmt.Sprintf("%s/v0.1/apps/%s/register-ssh-key", r.endpoint, appSlug)
+	tflog.Info(ctx, "SSH key updated successfully")
 
-	// Write private key to temporary file
-	filePath := "testtfkey"
-	fileContent := []byte(data.AuthSSHPrivateKey)
-	err := os.WriteFile(filePath, fileContent, 0644)
-	if err != nil {
-		tflog.Error(ctx, "Error writing to file", map[string]interface{}{"error": err.Error()})
-		resp.Diagnostics.AddError("File Error", fmt.Sprintf("Unable to write private key: %s", err))
-		return
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AppSSHResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// registerKey validates the keypair held in memory and registers it on the
+// app, recording the public key fingerprint in data.
+func (r *AppSSHResource) registerKey(ctx context.Context, data *AppSSHResourceModel, diags *diag.Diagnostics) bool {
+	// An unknown private key means ModifyPlan planned a new generated keypair
+	if data.AuthSSHPrivateKey.IsUnknown() {
+		if !generateKey(ctx, data, diags) {
+			return false
+		}
 	}
-	defer os.Remove(filePath)
 
-	// Read private key from file
-	privateKeyBytes, err := os.ReadFile(filePath)
+	pub, err := parseSSHKeypair(data.AuthSSHPrivateKey.ValueString(), data.AuthSSHPublicKey.ValueString())
 	if err != nil {
-		tflog.Error(ctx, "Error reading private key file", map[string]interface{}{"error": err.Error()})
-		resp.Diagnostics.AddError("File Error", fmt.Sprintf("Unable to read private key: %s", err))
-		return
+		diags.AddAttributeError(path.Root("auth_ssh_private_key"), "Invalid SSH Keypair", err.Error())
+		return false
 	}
-	privateKey := string(privateKeyBytes)
 
-	payload := Payload{
-		AuthSSHPrivateKey:                privateKey,
-		AuthSSHPublicKey:                 data.AuthSSHPublicKey,
-		IsRegisterKeyIntoProviderService: data.IsRegisterKeyIntoProviderService,
+	params := bitrise.RegisterSSHKeyParams{
+		AuthSSHPrivateKey:                data.AuthSSHPrivateKey.ValueString(),
+		AuthSSHPublicKey:                 data.AuthSSHPublicKey.ValueString(),
+		IsRegisterKeyIntoProviderService: data.IsRegisterKeyIntoProviderService.ValueBool(),
 	}
 
-	// Marshal the payload struct into a JSON string
-	payloadJSON, err := json.Marshal(payload)
-	if err != nil {
-		tflog.Error(ctx, "Error marshaling JSON payload", map[string]interface{}{"error": err.Error()})
-		resp.Diagnostics.AddError("JSON Error", fmt.Sprintf("Unable to marshal payload: %s", err))
-		return
+	if err := r.client.RegisterSSHKey(ctx, data.AppSlug.ValueString(), params); err != nil {
+		tflog.Error(ctx, "Request did not succeed", map[string]interface{}{"error": err.Error()})
+		diags.AddError("API Request Error", fmt.Sprintf("Unable to register SSH key: %s", err))
+		return false
 	}
 
-	tflog.Debug(ctx, "MODULEDEBUG: Update request payload", map[string]interface{}{"payload_json": string(payloadJSON)})
+	data.Fingerprint = types.StringValue(ssh.FingerprintSHA256(pub))
 
-	// Create an HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", completeURL, strings.NewReader(string(payloadJSON)))
-	if err != nil {
-		tflog.Error(ctx, "Error creating HTTP request", map[string]interface{}{"error": err.Error()})
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create request: %s", err))
-		return
-	}
+	return true
+}
 
-	// Send the HTTP request
-	httpResp, err := client.Do(httpReq)
-	if err != nil {
-		tflog.Error(ctx, "Error sending HTTP request", map[string]interface{}{"error": err.Error()})
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update SSH key: %s", err))
-		return
+// generateKey fills data with a new keypair according to its generate_key
+// settings.
+func generateKey(ctx context.Context, data *AppSSHResourceModel, diags *diag.Diagnostics) bool {
+	var settings AppSSHGenerateKeyModel
+	diags.Append(data.GenerateKey.As(ctx, &settings, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return false
 	}
-	defer httpResp.Body.Close()
 
-	// Read the response body
-	responseBody, err := io.ReadAll(httpResp.Body)
+	privateKey, publicKey, err := generateSSHKeypair(settings.Algorithm.ValueString(), int(settings.RSABits.ValueInt64()))
 	if err != nil {
-		tflog.Error(ctx, "Error reading response body", map[string]interface{}{"error": err.Error()})
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read response: %s", err))
-		return
+		diags.AddAttributeError(path.Root("generate_key"), "SSH Key Generation Error", err.Error())
+		return false
 	}
 
-	tflog.Debug(ctx, "MODULEDEBUG: Response Body", map[string]interface{}{"body": string(responseBody)})
+	tflog.Debug(ctx, "MODULEDEBUG: Generated SSH keypair", map[string]interface{}{"algorithm": settings.Algorithm.ValueString()})
 
-	if httpResp.StatusCode != http.StatusOK {
-		tflog.Error(ctx, "Request did not succeed", map[string]interface{}{
-			"status": httpResp.Status,
-			"body":   string(responseBody),
-		})
-		resp.Diagnostics.AddError("API Request Error", fmt.Sprintf("Request did not succeed: %s - %s", httpResp.Status, string(responseBody)))
-		return
-	}
+	data.AuthSSHPrivateKey = types.StringValue(privateKey)
+	data.AuthSSHPublicKey = types.StringValue(publicKey)
 
-	tflog.Info(ctx, "SSH key updated successfully")
-
-	// Save updated data into Terraform state
-	resp.State.Set(ctx, &data)
-}
-
-func (r *AppSSHResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	return true
 }
@@ -0,0 +1,206 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"terraform-provider-bitrise/internal/bitrise"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ datasource.DataSource = &AppsDataSource{}
+
+func NewAppsDataSource() datasource.DataSource {
+	return &AppsDataSource{}
+}
+
+type AppsDataSource struct {
+	client *bitrise.Client
+}
+
+type AppsDataSourceModel struct {
+	ID               types.String   `tfsdk:"id"`
+	OrganizationSlug types.String   `tfsdk:"organization_slug"`
+	TitleRegex       types.String   `tfsdk:"title_regex"`
+	ProjectType      types.String   `tfsdk:"project_type"`
+	Repo             types.String   `tfsdk:"repo"`
+	SortBy           types.String   `tfsdk:"sort_by"`
+	Apps             []AppItemModel `tfsdk:"apps"`
+}
+
+type AppItemModel struct {
+	Slug        types.String `tfsdk:"slug"`
+	Title       types.String `tfsdk:"title"`
+	RepoURL     types.String `tfsdk:"repo_url"`
+	ProjectType types.String `tfsdk:"project_type"`
+	Repo        types.String `tfsdk:"repo"`
+}
+
+func (d *AppsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_apps"
+}
+
+func (d *AppsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the Bitrise apps the token has access to, or the apps of an organization.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Data source identifier",
+				Computed:            true,
+			},
+			"organization_slug": schema.StringAttribute{
+				MarkdownDescription: "Only list the apps of this organization",
+				Optional:            true,
+			},
+			"title_regex": schema.StringAttribute{
+				MarkdownDescription: "Only list apps whose title matches this regular expression",
+				Optional:            true,
+			},
+			"project_type": schema.StringAttribute{
+				MarkdownDescription: "Only list apps of this project type (e.g. ios, android)",
+				Optional:            true,
+			},
+			"repo": schema.StringAttribute{
+				MarkdownDescription: "Only list apps whose repository is hosted on this git provider (e.g. github)",
+				Optional:            true,
+			},
+			"sort_by": schema.StringAttribute{
+				MarkdownDescription: "Order of the apps, `last_build_at` or `created_at`. Defaults to the API order (`last_build_at`).",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("last_build_at", "created_at"),
+				},
+			},
+			"apps": schema.ListNestedAttribute{
+				MarkdownDescription: "The matching apps",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"slug": schema.StringAttribute{
+							MarkdownDescription: "Slug of the app",
+							Computed:            true,
+						},
+						"title": schema.StringAttribute{
+							MarkdownDescription: "Title of the app",
+							Computed:            true,
+						},
+						"repo_url": schema.StringAttribute{
+							MarkdownDescription: "Repository URL of the app",
+							Computed:            true,
+						},
+						"project_type": schema.StringAttribute{
+							MarkdownDescription: "Project type of the app",
+							Computed:            true,
+						},
+						"repo": schema.StringAttribute{
+							MarkdownDescription: "Git provider of the repository",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *AppsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*bitrise.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *bitrise.Client, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *AppsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.client.LogContext(ctx)
+
+	var data AppsDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var titleRegex *regexp.Regexp
+	if !data.TitleRegex.IsNull() {
+		var err error
+		if titleRegex, err = regexp.Compile(data.TitleRegex.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("title_regex"), "Invalid Regular Expression", err.Error())
+			return
+		}
+	}
+
+	params := bitrise.ListAppsParams{
+		SortBy:      data.SortBy.ValueString(),
+		ProjectType: data.ProjectType.ValueString(),
+	}
+
+	tflog.Debug(ctx, "Reading Bitrise apps", map[string]interface{}{
+		"organization_slug": data.OrganizationSlug.ValueString(),
+	})
+
+	var apps []bitrise.App
+	var err error
+	if orgSlug := data.OrganizationSlug.ValueString(); orgSlug != "" {
+		apps, err = d.client.ListOrganizationApps(ctx, orgSlug, params)
+	} else {
+		apps, err = d.client.ListApps(ctx, params)
+	}
+	if err != nil {
+		tflog.Error(ctx, "Failed to list apps", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to list apps: %s", err))
+		return
+	}
+
+	// The API order is kept, only the filters it does not support are applied here
+	items := make([]AppItemModel, 0, len(apps))
+	for _, app := range apps {
+		if titleRegex != nil && !titleRegex.MatchString(app.Title) {
+			continue
+		}
+		if !data.ProjectType.IsNull() && app.ProjectType != data.ProjectType.ValueString() {
+			continue
+		}
+		if !data.Repo.IsNull() && !strings.EqualFold(app.Provider, data.Repo.ValueString()) {
+			continue
+		}
+
+		items = append(items, AppItemModel{
+			Slug:        types.StringValue(app.Slug),
+			Title:       types.StringValue(app.Title),
+			RepoURL:     types.StringValue(app.RepoURL),
+			ProjectType: types.StringValue(app.ProjectType),
+			Repo:        types.StringValue(app.Provider),
+		})
+	}
+
+	data.ID = types.StringValue("apps")
+	if !data.OrganizationSlug.IsNull() {
+		data.ID = data.OrganizationSlug
+	}
+	data.Apps = items
+
+	tflog.Debug(ctx, "Successfully read Bitrise apps", map[string]interface{}{
+		"count": len(items),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -2,36 +2,49 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"terraform-provider-bitrise/internal/bitrise"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var _ datasource.DataSource = &AvailableStacksDataSource{}
 
-func NewAvailableStacksDataSource(clientCreator func(endpoint, token string) *http.Client, endpoint, token string) *AvailableStacksDataSource {
-	return &AvailableStacksDataSource{
-		clientCreator: clientCreator,
-		endpoint:      endpoint,
-		token:         token,
-	}
+func NewAvailableStacksDataSource() datasource.DataSource {
+	return &AvailableStacksDataSource{}
 }
 
 type AvailableStacksDataSource struct {
-	clientCreator func(endpoint, token string) *http.Client
-	endpoint      string
-	token         string
+	client *bitrise.Client
 }
 
 type AvailableStacksDataSourceModel struct {
-	ID         types.String   `tfsdk:"id"`
-	StackKeys  []types.String `tfsdk:"stack_keys"`
+	ID                types.String          `tfsdk:"id"`
+	OS                types.String          `tfsdk:"os"`
+	NameRegex         types.String          `tfsdk:"name_regex"`
+	ExcludeDeprecated types.Bool            `tfsdk:"exclude_deprecated"`
+	StackKeys         []types.String        `tfsdk:"stack_keys"`
+	Stacks            []AvailableStackModel `tfsdk:"stacks"`
+}
+
+type AvailableStackModel struct {
+	ID              types.String   `tfsdk:"id"`
+	Title           types.String   `tfsdk:"title"`
+	OS              types.String   `tfsdk:"os"`
+	ProjectTypes    []types.String `tfsdk:"project_types"`
+	Deprecated      types.Bool     `tfsdk:"deprecated"`
+	DeprecationDate types.String   `tfsdk:"deprecation_date"`
+	RemovalDate     types.String   `tfsdk:"removal_date"`
+	MachineTypes    []types.String `tfsdk:"machine_types"`
 }
 
 func (d *AvailableStacksDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -40,17 +53,71 @@ func (d *AvailableStacksDataSource) Metadata(ctx context.Context, req datasource
 
 func (d *AvailableStacksDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "Retrieves the list of all available stacks from Bitrise.",
+		MarkdownDescription: "Retrieves the list of all available stacks from Bitrise, sorted by stack ID with version numbers compared numerically.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				MarkdownDescription: "Data source identifier",
 				Computed:            true,
 			},
+			"os": schema.StringAttribute{
+				MarkdownDescription: "Only return stacks running on this operating system, e.g. `osx` or `linux`",
+				Optional:            true,
+			},
+			"name_regex": schema.StringAttribute{
+				MarkdownDescription: "Only return stacks whose ID matches this regular expression",
+				Optional:            true,
+			},
+			"exclude_deprecated": schema.BoolAttribute{
+				MarkdownDescription: "Leave out stacks that have a deprecation date",
+				Optional:            true,
+			},
 			"stack_keys": schema.ListAttribute{
-				MarkdownDescription: "List of all available stack IDs",
+				MarkdownDescription: "List of the matching stack IDs, in the same order as `stacks`",
 				Computed:            true,
 				ElementType:         types.StringType,
 			},
+			"stacks": schema.ListNestedAttribute{
+				MarkdownDescription: "The matching stacks",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "Stack ID, as used by `stack_id`",
+							Computed:            true,
+						},
+						"title": schema.StringAttribute{
+							MarkdownDescription: "Human readable name of the stack",
+							Computed:            true,
+						},
+						"os": schema.StringAttribute{
+							MarkdownDescription: "Operating system of the stack, `osx` or `linux`",
+							Computed:            true,
+						},
+						"project_types": schema.ListAttribute{
+							MarkdownDescription: "Project types the stack supports",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+						"deprecated": schema.BoolAttribute{
+							MarkdownDescription: "Whether the stack has a deprecation date",
+							Computed:            true,
+						},
+						"deprecation_date": schema.StringAttribute{
+							MarkdownDescription: "Date from which the stack is deprecated, empty if not announced",
+							Computed:            true,
+						},
+						"removal_date": schema.StringAttribute{
+							MarkdownDescription: "Date on which the stack will be removed, empty if not announced",
+							Computed:            true,
+						},
+						"machine_types": schema.ListAttribute{
+							MarkdownDescription: "Machine types the stack can run on",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -60,19 +127,21 @@ func (d *AvailableStacksDataSource) Configure(ctx context.Context, req datasourc
 		return
 	}
 
-	clientCreator, ok := req.ProviderData.(func(endpoint, token string) *http.Client)
+	client, ok := req.ProviderData.(*bitrise.Client)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected func(endpoint, token string) *http.Client, got: %T", req.ProviderData),
+			fmt.Sprintf("Expected *bitrise.Client, got: %T", req.ProviderData),
 		)
 		return
 	}
 
-	d.clientCreator = clientCreator
+	d.client = client
 }
 
 func (d *AvailableStacksDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.client.LogContext(ctx)
+
 	var data AvailableStacksDataSourceModel
 
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
@@ -80,58 +149,66 @@ func (d *AvailableStacksDataSource) Read(ctx context.Context, req datasource.Rea
 		return
 	}
 
-	tflog.Debug(ctx, "Reading Bitrise available stacks")
-
-	client := d.clientCreator(d.endpoint, d.token)
-	url := fmt.Sprintf("%s/v0.1/available-stacks", d.endpoint)
-
-	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating HTTP request", err.Error())
-		return
+	var nameRegex *regexp.Regexp
+	if !data.NameRegex.IsNull() {
+		var err error
+		if nameRegex, err = regexp.Compile(data.NameRegex.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("name_regex"), "Invalid Regular Expression", err.Error())
+			return
+		}
 	}
 
-	httpResp, err := client.Do(httpReq)
-	if err != nil {
-		resp.Diagnostics.AddError("Error sending HTTP request", err.Error())
-		return
-	}
-	defer httpResp.Body.Close()
+	tflog.Debug(ctx, "Reading Bitrise available stacks")
 
-	responseBody, err := io.ReadAll(httpResp.Body)
+	stacksMap, err := d.client.ListAvailableStacks(ctx)
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading response body", err.Error())
+		tflog.Error(ctx, "Failed to read available stacks", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to read available stacks: %s", err))
 		return
 	}
 
-	if httpResp.StatusCode != http.StatusOK {
-		tflog.Error(ctx, "Failed to read available stacks", map[string]interface{}{
-			"status": httpResp.Status,
-			"body":   string(responseBody),
-		})
-		resp.Diagnostics.AddError(
-			"API Error",
-			fmt.Sprintf("Failed to read available stacks: %s - %s", httpResp.Status, string(responseBody)),
-		)
-		return
+	ids := make([]string, 0, len(stacksMap))
+	for id := range stacksMap {
+		ids = append(ids, id)
 	}
+	sort.Slice(ids, func(i, j int) bool {
+		return naturalLess(ids[i], ids[j])
+	})
 
-	// Parse the response as a map to get the keys
-	var stacksMap map[string]interface{}
-	if err := json.Unmarshal(responseBody, &stacksMap); err != nil {
-		resp.Diagnostics.AddError("Error parsing response", err.Error())
-		return
-	}
+	stackKeys := make([]types.String, 0, len(ids))
+	stacks := make([]AvailableStackModel, 0, len(ids))
+	for _, id := range ids {
+		stack := stacksMap[id]
+		os := bitrise.StackOS(id, stack)
+		deprecated := stack.DeprecationDate != ""
 
-	// Extract the keys from the map
-	stackKeys := make([]types.String, 0, len(stacksMap))
-	for key := range stacksMap {
-		stackKeys = append(stackKeys, types.StringValue(key))
+		if !data.OS.IsNull() && !strings.EqualFold(os, data.OS.ValueString()) {
+			continue
+		}
+		if nameRegex != nil && !nameRegex.MatchString(id) {
+			continue
+		}
+		if data.ExcludeDeprecated.ValueBool() && deprecated {
+			continue
+		}
+
+		stackKeys = append(stackKeys, types.StringValue(id))
+		stacks = append(stacks, AvailableStackModel{
+			ID:              types.StringValue(id),
+			Title:           types.StringValue(stack.Title),
+			OS:              types.StringValue(os),
+			ProjectTypes:    stringValues(stack.ProjectTypes),
+			Deprecated:      types.BoolValue(deprecated),
+			DeprecationDate: types.StringValue(stack.DeprecationDate),
+			RemovalDate:     types.StringValue(stack.RemovalDate),
+			MachineTypes:    stringValues(stack.MachineTypes),
+		})
 	}
 
 	// Set the data
 	data.ID = types.StringValue("available-stacks")
 	data.StackKeys = stackKeys
+	data.Stacks = stacks
 
 	tflog.Debug(ctx, "Successfully read available stacks", map[string]interface{}{
 		"count": len(stackKeys),
@@ -139,3 +216,52 @@ func (d *AvailableStacksDataSource) Read(ctx context.Context, req datasource.Rea
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
+
+func stringValues(values []string) []types.String {
+	result := make([]types.String, 0, len(values))
+	for _, value := range values {
+		result = append(result, types.StringValue(value))
+	}
+	return result
+}
+
+// naturalLess orders strings with embedded numbers numerically, so that
+// osx-xcode-9.4.x sorts before osx-xcode-15.0.x. Strings that only differ in
+// leading zeros fall back to byte order to keep the sort deterministic.
+func naturalLess(a, b string) bool {
+	origA, origB := a, b
+	for a != "" && b != "" {
+		ca, cb := a[0], b[0]
+		if isDigit(ca) && isDigit(cb) {
+			na, ra := leadingNumber(a)
+			nb, rb := leadingNumber(b)
+			if na != nb {
+				return na < nb
+			}
+			a, b = ra, rb
+			continue
+		}
+		if ca != cb {
+			return ca < cb
+		}
+		a, b = a[1:], b[1:]
+	}
+
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return origA < origB
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func leadingNumber(s string) (uint64, string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	n, _ := strconv.ParseUint(s[:i], 10, 64)
+	return n, s[i:]
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"slices"
+	"sort"
+	"testing"
+)
+
+func TestNaturalLess(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"osx-xcode-9.4.x", "osx-xcode-15.0.x", true},
+		{"osx-xcode-15.0.x", "osx-xcode-9.4.x", false},
+		{"osx-xcode-15.0.x", "osx-xcode-15.0.x", false},
+		{"osx-xcode-15.0.x", "osx-xcode-15.0.x-edge", true},
+		{"ubuntu-jammy-22.04", "ubuntu-noble-24.04", true},
+		{"stack-2", "stack-10", true},
+		{"stack-02", "stack-10", true},
+		{"stack-010", "stack-9", false},
+		// Same number, different leading zeros
+		{"stack-07", "stack-7", true},
+		{"stack-7", "stack-07", false},
+		{"stack-007-a", "stack-7-b", true},
+		{"stack-7-b", "stack-007-a", false},
+		{"stack-7-a", "stack-007-b", true},
+		{"", "a", true},
+		{"a", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"<"+tt.b, func(t *testing.T) {
+			if got := naturalLess(tt.a, tt.b); got != tt.want {
+				t.Errorf("naturalLess(%q, %q) = %t, want %t", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNaturalLessSort(t *testing.T) {
+	want := []string{
+		"linux-docker-android-20.04",
+		"osx-xcode-9.4.x",
+		"osx-xcode-15.0.x",
+		"osx-xcode-015.1.x",
+		"osx-xcode-15.1.x",
+		"osx-xcode-16.0.x",
+		"osx-xcode-16.0.x-edge",
+	}
+
+	// The input order must not affect the result
+	for i := range want {
+		ids := slices.Concat(want[i:], want[:i])
+		slices.Reverse(ids)
+		sort.Slice(ids, func(i, j int) bool {
+			return naturalLess(ids[i], ids[j])
+		})
+		if !slices.Equal(ids, want) {
+			t.Errorf("sorted = %q, want %q", ids, want)
+		}
+	}
+}
@@ -2,10 +2,9 @@ package provider
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
+
+	"terraform-provider-bitrise/internal/bitrise"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
@@ -16,18 +15,12 @@ import (
 
 var _ datasource.DataSource = &OrgGroupsDataSource{}
 
-func NewOrgGroupsDataSource(clientCreator func(endpoint, token string) *http.Client, endpoint, token string) *OrgGroupsDataSource {
-	return &OrgGroupsDataSource{
-		clientCreator: clientCreator,
-		endpoint:      endpoint,
-		token:         token,
-	}
+func NewOrgGroupsDataSource() datasource.DataSource {
+	return &OrgGroupsDataSource{}
 }
 
 type OrgGroupsDataSource struct {
-	clientCreator func(endpoint, token string) *http.Client
-	endpoint      string
-	token         string
+	client *bitrise.Client
 }
 
 type OrgGroupsDataSourceModel struct {
@@ -36,11 +29,6 @@ type OrgGroupsDataSourceModel struct {
 	Groups  types.List   `tfsdk:"groups"`
 }
 
-type GroupAPIModel struct {
-	Slug string `json:"slug"`
-	Name string `json:"name"`
-}
-
 func (d *OrgGroupsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_org_groups"
 }
@@ -76,19 +64,21 @@ func (d *OrgGroupsDataSource) Configure(ctx context.Context, req datasource.Conf
 		return
 	}
 
-	clientCreator, ok := req.ProviderData.(func(endpoint, token string) *http.Client)
+	client, ok := req.ProviderData.(*bitrise.Client)
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected func(endpoint, token string) *http.Client, got: %T", req.ProviderData),
+			fmt.Sprintf("Expected *bitrise.Client, got: %T", req.ProviderData),
 		)
 		return
 	}
 
-	d.clientCreator = clientCreator
+	d.client = client
 }
 
 func (d *OrgGroupsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.client.LogContext(ctx)
+
 	var data OrgGroupsDataSourceModel
 
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
@@ -102,43 +92,10 @@ func (d *OrgGroupsDataSource) Read(ctx context.Context, req datasource.ReadReque
 		"org_slug": orgSlug,
 	})
 
-	client := d.clientCreator(d.endpoint, d.token)
-	url := fmt.Sprintf("%s/v0.1/organizations/%s/groups", d.endpoint, orgSlug)
-
-	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		resp.Diagnostics.AddError("Error creating HTTP request", err.Error())
-		return
-	}
-
-	httpResp, err := client.Do(httpReq)
-	if err != nil {
-		resp.Diagnostics.AddError("Error sending HTTP request", err.Error())
-		return
-	}
-	defer httpResp.Body.Close()
-
-	responseBody, err := io.ReadAll(httpResp.Body)
+	groupsResp, err := d.client.ListOrganizationGroups(ctx, orgSlug)
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading response body", err.Error())
-		return
-	}
-
-	if httpResp.StatusCode != http.StatusOK {
-		tflog.Error(ctx, "Failed to read organization groups", map[string]interface{}{
-			"status": httpResp.Status,
-			"body":   string(responseBody),
-		})
-		resp.Diagnostics.AddError(
-			"API Error",
-			fmt.Sprintf("Failed to read organization groups: %s - %s", httpResp.Status, string(responseBody)),
-		)
-		return
-	}
-
-	var groupsResp []GroupAPIModel
-	if err := json.Unmarshal(responseBody, &groupsResp); err != nil {
-		resp.Diagnostics.AddError("Error parsing response", err.Error())
+		tflog.Error(ctx, "Failed to read organization groups", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to read organization groups: %s", err))
 		return
 	}
 
@@ -2,32 +2,44 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"os"
+	"time"
 
+	"terraform-provider-bitrise/internal/bitrise"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var _ provider.Provider = &BitriseProvider{}
 
-type ClientProvider interface {
-	GetClient() (*http.Client, error)
-}
+const (
+	defaultEndpoint = "https://api.bitrise.io"
+	endpointEnvVar  = "BITRISE_API_ENDPOINT"
+	tokenEnvVar     = "BITRISE_TOKEN"
+)
 
 type BitriseProvider struct {
-	version       string
-	endpoint      string
-	token         string
-	clientCreator func(endpoint, token string) *http.Client
+	version string
 }
 
 type BitriseProviderModel struct {
-	Endpoint types.String `tfsdk:"endpoint"`
-	Token    types.String `tfsdk:"token"`
+	Endpoint     types.String `tfsdk:"endpoint"`
+	Token        types.String `tfsdk:"token"`
+	MaxRetries   types.Int64  `tfsdk:"max_retries"`
+	RetryWaitMin types.Int64  `tfsdk:"retry_wait_min"`
+	RetryWaitMax types.Int64  `tfsdk:"retry_wait_max"`
+
+	SensitiveLogFields []types.String `tfsdk:"sensitive_log_fields"`
 }
 
 func (p *BitriseProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -39,12 +51,39 @@ func (p *BitriseProvider) Schema(ctx context.Context, req provider.SchemaRequest
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"endpoint": schema.StringAttribute{
-				MarkdownDescription: "The endpoint of the Bitrise API",
+				MarkdownDescription: "The endpoint of the Bitrise API. Can also be set with the `BITRISE_API_ENDPOINT` environment variable. Default: `https://api.bitrise.io`",
 				Optional:            true,
 			},
 			"token": schema.StringAttribute{
-				MarkdownDescription: "The API token for authenticating with the Bitrise API",
+				MarkdownDescription: "The API token for authenticating with the Bitrise API. Can also be set with the `BITRISE_TOKEN` environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"max_retries": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of retries for rate limited (429) and transient (502, 503, 504) API responses. Set to 0 to disable retries. Default: 4",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"retry_wait_min": schema.Int64Attribute{
+				MarkdownDescription: "Minimum time in seconds to wait before retrying a request. The wait doubles on every attempt. Default: 1",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"retry_wait_max": schema.Int64Attribute{
+				MarkdownDescription: "Maximum time in seconds to wait between retries. When the API asks for a longer wait via `Retry-After` or `X-RateLimit-Reset`, the request is not retried and fails with the API response. Default: 30",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"sensitive_log_fields": schema.ListAttribute{
+				MarkdownDescription: "Additional JSON field names whose values are masked in debug logs. The `Authorization` header, `value`, `auth_ssh_private_key` and `app_config_datastore_yaml` are always masked.",
 				Optional:            true,
+				ElementType:         types.StringType,
 			},
 		},
 	}
@@ -61,86 +100,119 @@ func (p *BitriseProvider) Configure(ctx context.Context, req provider.ConfigureR
 		return
 	}
 
-	// Get the actual string values using ValueString()
-	endpoint := config.Endpoint.ValueString()
-	token := config.Token.ValueString()
+	if config.Endpoint.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("endpoint"),
+			"Unknown Bitrise API Endpoint",
+			"The provider cannot create the Bitrise API client as there is an unknown configuration value for the endpoint. "+
+				"Either set the value statically in the configuration, or use the BITRISE_API_ENDPOINT environment variable.",
+		)
+	}
+	if config.Token.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("token"),
+			"Unknown Bitrise API Token",
+			"The provider cannot create the Bitrise API client as there is an unknown configuration value for the token. "+
+				"Either set the value statically in the configuration, or use the BITRISE_TOKEN environment variable.",
+		)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Configuration values take precedence over the environment
+	endpoint := os.Getenv(endpointEnvVar)
+	if !config.Endpoint.IsNull() {
+		endpoint = config.Endpoint.ValueString()
+	}
+	if endpoint == "" {
+		endpoint = defaultEndpoint
+	}
 
-	// Store the endpoint as-is (with https:// if provided)
-	p.endpoint = endpoint
-	p.token = token
+	token := os.Getenv(tokenEnvVar)
+	if !config.Token.IsNull() {
+		token = config.Token.ValueString()
+	}
+	if token == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("token"),
+			"Missing Bitrise API Token",
+			"The provider cannot create the Bitrise API client as there is no API token configured. "+
+				"Set the token attribute in the provider configuration or use the BITRISE_TOKEN environment variable.",
+		)
+		return
+	}
 
 	// Log configuration for debugging
 	tflog.Debug(ctx, "MODULEDEBUG: Configuring Bitrise provider", map[string]interface{}{
-		"endpoint": p.endpoint,
+		"endpoint": endpoint,
 	})
 
-	p.clientCreator = func(endpoint, token string) *http.Client {
-		return &http.Client{
-			Transport: &authenticatedTransport{
-				token:    token,
-				base:     http.DefaultTransport,
-				headers:  map[string]string{"Content-Type": "application/json"},
-				endpoint: endpoint,
-			},
-		}
+	transport := &authenticatedTransport{
+		token:        token,
+		base:         http.DefaultTransport,
+		headers:      map[string]string{"Content-Type": "application/json"},
+		endpoint:     endpoint,
+		maxRetries:   defaultMaxRetries,
+		retryWaitMin: defaultRetryWaitMin,
+		retryWaitMax: defaultRetryWaitMax,
+	}
+	if !config.MaxRetries.IsNull() {
+		transport.maxRetries = int(config.MaxRetries.ValueInt64())
+	}
+	if !config.RetryWaitMin.IsNull() {
+		transport.retryWaitMin = time.Duration(config.RetryWaitMin.ValueInt64()) * time.Second
+	}
+	if !config.RetryWaitMax.IsNull() {
+		transport.retryWaitMax = time.Duration(config.RetryWaitMax.ValueInt64()) * time.Second
+	}
+	if transport.retryWaitMax < transport.retryWaitMin {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("retry_wait_max"),
+			"Invalid Retry Configuration",
+			fmt.Sprintf("retry_wait_max (%s) must not be lower than retry_wait_min (%s).", transport.retryWaitMax, transport.retryWaitMin),
+		)
+		return
 	}
 
-	resp.DataSourceData = p.clientCreator
-	resp.ResourceData = p.clientCreator
+	httpClient := &http.Client{
+		Transport: transport,
+	}
+	sensitiveFields := make([]string, 0, len(config.SensitiveLogFields))
+	for _, field := range config.SensitiveLogFields {
+		sensitiveFields = append(sensitiveFields, field.ValueString())
+	}
+	client := bitrise.NewClient(endpoint, httpClient, sensitiveFields...)
+
+	resp.DataSourceData = client
+	resp.ResourceData = client
 
 	tflog.Info(ctx, "Bitrise provider configured successfully")
 }
 
 func (p *BitriseProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
-		func() resource.Resource {
-			return NewAppResource(p.clientCreator, p.endpoint, p.token)
-		},
-		func() resource.Resource {
-			return NewAppSSHResource(p.clientCreator, p.endpoint, p.token) // Return the custom resource type instance
-		},
-		func() resource.Resource {
-			return NewAppFinishResource(p.clientCreator, p.endpoint, p.token) // Return the custom resource type instance
-		},
-		func() resource.Resource {
-			return NewAppSecretsResource(p.clientCreator, p.endpoint, p.token) // Secrets resource
-		},
-		func() resource.Resource {
-			return NewAppBitriseYmlResource(p.clientCreator, p.endpoint, p.token) // Bitrise.yml resource
-		},
-		func() resource.Resource {
-			return NewAppRolesResource(p.clientCreator, p.endpoint, p.token) // Roles resource
-		},
+		NewAppResource,
+		NewAppSSHResource,
+		NewAppFinishResource,
+		NewAppSecretsResource,      // Secrets resource
+		NewAppSecretsBulkResource,  // Bulk secrets resource
+		NewWorkspaceSecretResource, // Workspace secrets resource
+		NewAppBitriseYmlResource,   // Bitrise.yml resource
+		NewAppRolesResource,        // Roles resource
+		NewAppOnboardingResource,   // Onboarding resource
 	}
 }
 
-type authenticatedTransport struct {
-	token    string
-	endpoint string
-	base     http.RoundTripper
-	headers  map[string]string
-}
-
-func (t *authenticatedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	req.Header.Set("Authorization", t.token)
-	// Apply all headers from the headers map
-	for key, value := range t.headers {
-		req.Header.Set(key, value)
-	}
-	return t.base.RoundTrip(req)
-}
-
 func (p *BitriseProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
-		func() datasource.DataSource {
-			return NewAppRolesDataSource(p.clientCreator, p.endpoint, p.token)
-		},
-		func() datasource.DataSource {
-			return NewOrgGroupsDataSource(p.clientCreator, p.endpoint, p.token)
-		},
-		func() datasource.DataSource {
-			return NewAvailableStacksDataSource(p.clientCreator, p.endpoint, p.token)
-		},
+		NewAppRolesDataSource,
+		NewOrgGroupsDataSource,
+		NewAvailableStacksDataSource,
+		NewAppDataSource,
+		NewAppsDataSource,
+		NewAppSecretsDataSource,
+		NewWorkspaceSecretDataSource,
 	}
 }
 
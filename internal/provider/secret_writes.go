@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"terraform-provider-bitrise/internal/bitrise"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// secretWritesKey is the private state key holding, per secret name, the
+// update timestamp the API reported after Terraform last wrote the secret.
+const secretWritesKey = "secret_writes"
+
+// secretWrites maps secret names to the update timestamp of their last write
+// by Terraform. Protected values cannot be read back, so a newer timestamp
+// is the only sign of a change made outside of Terraform.
+type secretWrites map[string]string
+
+// secretGetter reads a single secret by name, binding the app or workspace
+// the secret belongs to.
+type secretGetter func(ctx context.Context, name string) (*bitrise.Secret, error)
+
+func appSecretGetter(client *bitrise.Client, appSlug string) secretGetter {
+	return func(ctx context.Context, name string) (*bitrise.Secret, error) {
+		return client.GetSecret(ctx, appSlug, name)
+	}
+}
+
+func workspaceSecretGetter(client *bitrise.Client, workspaceSlug string) secretGetter {
+	return func(ctx context.Context, name string) (*bitrise.Secret, error) {
+		return client.GetWorkspaceSecret(ctx, workspaceSlug, name)
+	}
+}
+
+func loadSecretWrites(ctx context.Context, private privateState, diags *diag.Diagnostics) secretWrites {
+	writes := secretWrites{}
+
+	raw, d := private.GetKey(ctx, secretWritesKey)
+	diags.Append(d...)
+	if len(raw) == 0 {
+		return writes
+	}
+
+	if err := json.Unmarshal(raw, &writes); err != nil {
+		diags.AddError("Invalid Private State", fmt.Sprintf("Unable to parse the secret write timestamps: %s", err))
+	}
+
+	return writes
+}
+
+func saveSecretWrites(ctx context.Context, private privateState, writes secretWrites, diags *diag.Diagnostics) {
+	raw, err := json.Marshal(writes)
+	if err != nil {
+		diags.AddError("Invalid Private State", fmt.Sprintf("Unable to encode the secret write timestamps: %s", err))
+		return
+	}
+
+	diags.Append(private.SetKey(ctx, secretWritesKey, raw)...)
+}
+
+// changedOutside reports whether a protected secret was written since
+// Terraform last wrote it. Secrets without a recorded write, e.g. imported
+// ones, and APIs not reporting update timestamps never report a change.
+func (w secretWrites) changedOutside(secret *bitrise.Secret) bool {
+	recorded := w[secret.Name]
+	return secret.IsProtected && secret.UpdatedAt != "" && recorded != "" && secret.UpdatedAt != recorded
+}
+
+// observe records the timestamp of a secret unless one is recorded already,
+// which adopts secrets imported or written before timestamps were tracked.
+func (w secretWrites) observe(secret *bitrise.Secret) {
+	if _, ok := w[secret.Name]; !ok && secret.UpdatedAt != "" {
+		w[secret.Name] = secret.UpdatedAt
+	}
+}
+
+// recordSecretWrite records the update timestamp of a secret Terraform just
+// wrote, on top of the records in prior. Without a timestamp the record is
+// dropped, and the next Read adopts the one it finds.
+func recordSecretWrite(ctx context.Context, get secretGetter, name string, prior, private privateState, diags *diag.Diagnostics) {
+	writes := loadSecretWrites(ctx, prior, diags)
+
+	secret, err := get(ctx, name)
+	switch {
+	case err != nil:
+		tflog.Warn(ctx, "Unable to read back written secret", map[string]interface{}{"error": err.Error()})
+		delete(writes, name)
+	case secret.UpdatedAt == "":
+		delete(writes, name)
+	default:
+		writes[name] = secret.UpdatedAt
+	}
+
+	saveSecretWrites(ctx, private, writes, diags)
+}
+
+// refreshSecretWrites checks a secret read by Read against the records in
+// prior and stores the updated records. It reports, with a warning, whether
+// a protected secret was written outside of Terraform. Its value cannot be
+// compared, so the caller plans the configured value to be written again.
+func refreshSecretWrites(ctx context.Context, secret *bitrise.Secret, owner string, prior, private privateState, diags *diag.Diagnostics) bool {
+	writes := loadSecretWrites(ctx, prior, diags)
+
+	changed := writes.changedOutside(secret)
+	if changed {
+		addProtectedSecretDriftWarning(diags, owner, secret.Name)
+	} else {
+		writes.observe(secret)
+	}
+
+	saveSecretWrites(ctx, private, writes, diags)
+	return changed
+}
+
+// addProtectedSecretDriftWarning reports a protected secret modified outside
+// of Terraform, whose configured value is written again by the next apply.
+// owner names the app or workspace of the secret, e.g. "app <slug>".
+func addProtectedSecretDriftWarning(diags *diag.Diagnostics, owner, name string) {
+	diags.AddWarning("Protected Secret Changed Outside Terraform",
+		fmt.Sprintf("Secret %s of %s was modified after Terraform last wrote it. Its value cannot be read back, so the next apply writes the configured value again.", name, owner))
+}
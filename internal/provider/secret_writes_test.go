@@ -0,0 +1,220 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"reflect"
+	"testing"
+
+	"terraform-provider-bitrise/internal/bitrise"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// fakePrivate is an in-memory privateState. Like the framework, an empty
+// value removes the key.
+type fakePrivate map[string][]byte
+
+func (p fakePrivate) GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics) {
+	return p[key], nil
+}
+
+func (p fakePrivate) SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics {
+	if len(value) == 0 {
+		delete(p, key)
+		return nil
+	}
+	p[key] = value
+	return nil
+}
+
+// initPrivate points field, the address of the Private field of a framework
+// request or response, to private state holding keys. The type is internal
+// to the framework, so it is created through reflection.
+func initPrivate(t *testing.T, field any, keys map[string][]byte) {
+	t.Helper()
+
+	v := reflect.ValueOf(field).Elem()
+	v.Set(reflect.New(v.Type().Elem()))
+
+	private := v.Interface().(privateState)
+	for key, value := range keys {
+		if diags := private.SetKey(context.Background(), key, value); diags.HasError() {
+			t.Fatalf("SetKey(%s) diagnostics = %v", key, diags)
+		}
+	}
+}
+
+func mustJSON(t *testing.T, v any) []byte {
+	t.Helper()
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func TestSecretWritesChangedOutside(t *testing.T) {
+	tests := []struct {
+		name     string
+		recorded string
+		secret   bitrise.Secret
+		want     bool
+	}{
+		{
+			name:   "no record",
+			secret: bitrise.Secret{Name: "API_KEY", IsProtected: true, UpdatedAt: "2025-01-02T10:00:00Z"},
+		},
+		{
+			name:     "same timestamp",
+			recorded: "2025-01-02T10:00:00Z",
+			secret:   bitrise.Secret{Name: "API_KEY", IsProtected: true, UpdatedAt: "2025-01-02T10:00:00Z"},
+		},
+		{
+			name:     "newer timestamp",
+			recorded: "2025-01-02T10:00:00Z",
+			secret:   bitrise.Secret{Name: "API_KEY", IsProtected: true, UpdatedAt: "2025-01-03T08:30:00Z"},
+			want:     true,
+		},
+		{
+			name:     "newer timestamp of a readable secret",
+			recorded: "2025-01-02T10:00:00Z",
+			secret:   bitrise.Secret{Name: "API_KEY", UpdatedAt: "2025-01-03T08:30:00Z"},
+		},
+		{
+			name:     "no timestamp reported",
+			recorded: "2025-01-02T10:00:00Z",
+			secret:   bitrise.Secret{Name: "API_KEY", IsProtected: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writes := secretWrites{}
+			if tt.recorded != "" {
+				writes[tt.secret.Name] = tt.recorded
+			}
+
+			if got := writes.changedOutside(&tt.secret); got != tt.want {
+				t.Errorf("changedOutside() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefreshSecretWrites(t *testing.T) {
+	tests := []struct {
+		name        string
+		prior       secretWrites
+		secret      bitrise.Secret
+		wantChanged bool
+		wantWrites  secretWrites
+	}{
+		{
+			name:       "missing timestamp adopted",
+			prior:      secretWrites{"OTHER": "2025-01-01T00:00:00Z"},
+			secret:     bitrise.Secret{Name: "API_KEY", IsProtected: true, UpdatedAt: "2025-01-02T10:00:00Z"},
+			wantWrites: secretWrites{"OTHER": "2025-01-01T00:00:00Z", "API_KEY": "2025-01-02T10:00:00Z"},
+		},
+		{
+			name:       "no private state",
+			secret:     bitrise.Secret{Name: "API_KEY", IsProtected: true, UpdatedAt: "2025-01-02T10:00:00Z"},
+			wantWrites: secretWrites{"API_KEY": "2025-01-02T10:00:00Z"},
+		},
+		{
+			name:       "unchanged",
+			prior:      secretWrites{"API_KEY": "2025-01-02T10:00:00Z"},
+			secret:     bitrise.Secret{Name: "API_KEY", IsProtected: true, UpdatedAt: "2025-01-02T10:00:00Z"},
+			wantWrites: secretWrites{"API_KEY": "2025-01-02T10:00:00Z"},
+		},
+		{
+			// The record is kept, so the secret stays flagged until written
+			name:        "newer timestamp flagged",
+			prior:       secretWrites{"API_KEY": "2025-01-02T10:00:00Z"},
+			secret:      bitrise.Secret{Name: "API_KEY", IsProtected: true, UpdatedAt: "2025-01-03T08:30:00Z"},
+			wantChanged: true,
+			wantWrites:  secretWrites{"API_KEY": "2025-01-02T10:00:00Z"},
+		},
+		{
+			name:       "no timestamp reported",
+			secret:     bitrise.Secret{Name: "API_KEY", IsProtected: true},
+			wantWrites: secretWrites{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			prior := fakePrivate{}
+			if tt.prior != nil {
+				prior[secretWritesKey] = mustJSON(t, tt.prior)
+			}
+			private := maps.Clone(prior)
+
+			var diags diag.Diagnostics
+			changed := refreshSecretWrites(ctx, &tt.secret, "app app-slug", prior, private, &diags)
+			if diags.HasError() {
+				t.Fatalf("refreshSecretWrites() diagnostics = %v", diags)
+			}
+
+			if changed != tt.wantChanged || diags.WarningsCount() > 0 != tt.wantChanged {
+				t.Errorf("refreshSecretWrites() = %t with %d warnings, want %t", changed, diags.WarningsCount(), tt.wantChanged)
+			}
+			if got := loadSecretWrites(ctx, private, &diags); !maps.Equal(got, tt.wantWrites) {
+				t.Errorf("stored writes = %v, want %v", got, tt.wantWrites)
+			}
+		})
+	}
+}
+
+func TestRecordSecretWrite(t *testing.T) {
+	tests := []struct {
+		name       string
+		secret     *bitrise.Secret
+		err        error
+		wantWrites secretWrites
+	}{
+		{
+			name:       "timestamp recorded",
+			secret:     &bitrise.Secret{Name: "API_KEY", IsProtected: true, UpdatedAt: "2025-01-03T08:30:00Z"},
+			wantWrites: secretWrites{"OTHER": "2025-01-01T00:00:00Z", "API_KEY": "2025-01-03T08:30:00Z"},
+		},
+		{
+			name:       "no timestamp reported",
+			secret:     &bitrise.Secret{Name: "API_KEY", IsProtected: true},
+			wantWrites: secretWrites{"OTHER": "2025-01-01T00:00:00Z"},
+		},
+		{
+			name:       "read back failed",
+			err:        errors.New("connection reset"),
+			wantWrites: secretWrites{"OTHER": "2025-01-01T00:00:00Z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			prior := fakePrivate{secretWritesKey: mustJSON(t, secretWrites{
+				"OTHER":   "2025-01-01T00:00:00Z",
+				"API_KEY": "2025-01-02T10:00:00Z",
+			})}
+			private := maps.Clone(prior)
+			get := func(ctx context.Context, name string) (*bitrise.Secret, error) {
+				return tt.secret, tt.err
+			}
+
+			var diags diag.Diagnostics
+			recordSecretWrite(ctx, get, "API_KEY", prior, private, &diags)
+			if diags.HasError() {
+				t.Fatalf("recordSecretWrite() diagnostics = %v", diags)
+			}
+
+			if got := loadSecretWrites(ctx, private, &diags); !maps.Equal(got, tt.wantWrites) {
+				t.Errorf("stored writes = %v, want %v", got, tt.wantWrites)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	sshKeyAlgorithmED25519 = "ed25519"
+	sshKeyAlgorithmRSA     = "rsa"
+
+	defaultSSHKeyRSABits = 4096
+	minSSHKeyRSABits     = 2048
+)
+
+// generateSSHKeypair creates a new keypair and returns the private key PEM
+// encoded (OpenSSH format for ed25519, PKCS#1 for RSA) and the public key in
+// authorized_keys format.
+func generateSSHKeypair(algorithm string, rsaBits int) (string, string, error) {
+	var privateKey interface{}
+	var privateBlock *pem.Block
+
+	switch algorithm {
+	case sshKeyAlgorithmED25519:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return "", "", fmt.Errorf("unable to generate ed25519 key: %w", err)
+		}
+		if privateBlock, err = ssh.MarshalPrivateKey(key, ""); err != nil {
+			return "", "", fmt.Errorf("unable to encode ed25519 key: %w", err)
+		}
+		privateKey = key
+	case sshKeyAlgorithmRSA:
+		key, err := rsa.GenerateKey(rand.Reader, rsaBits)
+		if err != nil {
+			return "", "", fmt.Errorf("unable to generate %d bit RSA key: %w", rsaBits, err)
+		}
+		privateBlock = &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+		privateKey = key
+	default:
+		return "", "", fmt.Errorf("unsupported key algorithm %q", algorithm)
+	}
+
+	signer, err := ssh.NewSignerFromKey(privateKey)
+	if err != nil {
+		return "", "", fmt.Errorf("unable to derive public key: %w", err)
+	}
+
+	publicKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey())))
+
+	return string(pem.EncodeToMemory(privateBlock)), publicKey, nil
+}
+
+// parseSSHKeypair validates that privateKey is an unencrypted PEM or OpenSSH
+// private key and that publicKey, in authorized_keys format, belongs to it.
+// The parsed public key is returned for fingerprinting.
+func parseSSHKeypair(privateKey, publicKey string) (ssh.PublicKey, error) {
+	signer, err := ssh.ParsePrivateKey([]byte(privateKey))
+	if err != nil {
+		var passphraseErr *ssh.PassphraseMissingError
+		if errors.As(err, &passphraseErr) {
+			return nil, fmt.Errorf("the private key is encrypted with a passphrase, Bitrise requires an unencrypted key")
+		}
+		return nil, fmt.Errorf("the private key is not a valid PEM or OpenSSH private key: %w", err)
+	}
+
+	pub, err := parseSSHPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(signer.PublicKey().Marshal(), pub.Marshal()) {
+		return nil, fmt.Errorf("the public key does not match the private key (expected %s, got %s)",
+			ssh.FingerprintSHA256(signer.PublicKey()), ssh.FingerprintSHA256(pub))
+	}
+
+	return pub, nil
+}
+
+// parseSSHPublicKey parses a public key in authorized_keys format.
+func parseSSHPublicKey(publicKey string) (ssh.PublicKey, error) {
+	pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(strings.TrimSpace(publicKey)))
+	if err != nil {
+		return nil, fmt.Errorf("the public key is not a valid OpenSSH public key: %w", err)
+	}
+
+	return pub, nil
+}
@@ -0,0 +1,182 @@
+package provider
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// testSSHKeypair returns a new ed25519 keypair, the private key in OpenSSH
+// format, optionally encrypted, and the public key in authorized_keys format.
+func testSSHKeypair(t *testing.T, passphrase string) (string, string) {
+	t.Helper()
+
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var block *pem.Block
+	if passphrase != "" {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(key, "", []byte(passphrase))
+	} else {
+		block, err = ssh.MarshalPrivateKey(key, "")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(block)), strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub)))
+}
+
+func TestParseSSHKeypair(t *testing.T) {
+	privateKey, publicKey := testSSHKeypair(t, "")
+	_, otherPublicKey := testSSHKeypair(t, "")
+	encryptedKey, encryptedPublicKey := testSSHKeypair(t, "secret")
+	certificate := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("not a key")}))
+
+	tests := []struct {
+		name       string
+		privateKey string
+		publicKey  string
+		wantErr    string
+	}{
+		{
+			name:       "matching",
+			privateKey: privateKey,
+			publicKey:  publicKey,
+		},
+		{
+			name:       "matching with comment and newline",
+			privateKey: privateKey,
+			publicKey:  publicKey + " deploy@bitrise\n",
+		},
+		{
+			name:       "mismatched",
+			privateKey: privateKey,
+			publicKey:  otherPublicKey,
+			wantErr:    "the public key does not match the private key",
+		},
+		{
+			name:       "encrypted",
+			privateKey: encryptedKey,
+			publicKey:  encryptedPublicKey,
+			wantErr:    "the private key is encrypted with a passphrase",
+		},
+		{
+			name:       "unsupported key type",
+			privateKey: certificate,
+			publicKey:  publicKey,
+			wantErr:    "the private key is not a valid PEM or OpenSSH private key",
+		},
+		{
+			name:       "not pem",
+			privateKey: "ssh-ed25519 AAAA",
+			publicKey:  publicKey,
+			wantErr:    "the private key is not a valid PEM or OpenSSH private key",
+		},
+		{
+			name:       "invalid public key",
+			privateKey: privateKey,
+			publicKey:  "ssh-ed25519 not-base64",
+			wantErr:    "the public key is not a valid OpenSSH public key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pub, err := parseSSHKeypair(tt.privateKey, tt.publicKey)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("parseSSHKeypair() error = %s", err)
+				}
+				if got := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pub))); got != publicKey {
+					t.Errorf("parseSSHKeypair() = %s, want %s", got, publicKey)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseSSHKeypair() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerateSSHKeypair(t *testing.T) {
+	tests := []struct {
+		name      string
+		algorithm string
+		rsaBits   int
+		wantType  string
+		wantErr   bool
+	}{
+		{
+			name:      "ed25519",
+			algorithm: sshKeyAlgorithmED25519,
+			wantType:  ssh.KeyAlgoED25519,
+		},
+		{
+			name:      "rsa",
+			algorithm: sshKeyAlgorithmRSA,
+			rsaBits:   minSSHKeyRSABits,
+			wantType:  ssh.KeyAlgoRSA,
+		},
+		{
+			name:      "unsupported algorithm",
+			algorithm: "dsa",
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			privateKey, publicKey, err := generateSSHKeypair(tt.algorithm, tt.rsaBits)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("generateSSHKeypair() did not return an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("generateSSHKeypair() error = %s", err)
+			}
+
+			// The generated pair has to pass the same validation as a
+			// configured one, and the fingerprint must not depend on which
+			// side it is derived from
+			pub, err := parseSSHKeypair(privateKey, publicKey)
+			if err != nil {
+				t.Fatalf("parseSSHKeypair() error = %s", err)
+			}
+			if pub.Type() != tt.wantType {
+				t.Errorf("key type = %s, want %s", pub.Type(), tt.wantType)
+			}
+
+			signer, err := ssh.ParsePrivateKey([]byte(privateKey))
+			if err != nil {
+				t.Fatal(err)
+			}
+			reparsed, err := parseSSHPublicKey(publicKey + " deploy@bitrise\n")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			fingerprint := ssh.FingerprintSHA256(pub)
+			if got := ssh.FingerprintSHA256(signer.PublicKey()); got != fingerprint {
+				t.Errorf("private key fingerprint = %s, want %s", got, fingerprint)
+			}
+			if got := ssh.FingerprintSHA256(reparsed); got != fingerprint {
+				t.Errorf("public key fingerprint = %s, want %s", got, fingerprint)
+			}
+		})
+	}
+}
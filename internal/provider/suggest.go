@@ -0,0 +1,62 @@
+package provider
+
+import (
+	"sort"
+)
+
+// closestMatches returns up to n candidates ordered by their edit distance to
+// target, used to suggest valid values in diagnostics.
+func closestMatches(target string, candidates []string, n int) []string {
+	type match struct {
+		value    string
+		distance int
+	}
+
+	matches := make([]match, 0, len(candidates))
+	for _, candidate := range candidates {
+		matches = append(matches, match{value: candidate, distance: levenshtein(target, candidate)})
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].value < matches[j].value
+	})
+
+	if len(matches) > n {
+		matches = matches[:n]
+	}
+
+	result := make([]string, 0, len(matches))
+	for _, m := range matches {
+		result = append(result, m.value)
+	}
+
+	return result
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
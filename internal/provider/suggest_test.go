@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestClosestMatches(t *testing.T) {
+	stacks := []string{
+		"osx-xcode-16.0.x",
+		"osx-xcode-15.4.x",
+		"osx-xcode-15.3.x",
+		"ubuntu-jammy-22.04-bitrise-2024",
+		"ubuntu-noble-24.04-bitrise-2025",
+	}
+
+	tests := []struct {
+		name       string
+		target     string
+		candidates []string
+		n          int
+		want       []string
+	}{
+		{
+			name:       "closest first",
+			target:     "osx-xcode-15.4",
+			candidates: stacks,
+			n:          1,
+			want:       []string{"osx-xcode-15.4.x"},
+		},
+		{
+			name:       "ties ordered by value",
+			target:     "osx-xcode-15.x.x",
+			candidates: stacks,
+			n:          3,
+			want:       []string{"osx-xcode-15.3.x", "osx-xcode-15.4.x", "osx-xcode-16.0.x"},
+		},
+		{
+			name:       "ties independent of candidate order",
+			target:     "abc",
+			candidates: []string{"abz", "aXc", "zbc"},
+			n:          3,
+			want:       []string{"aXc", "abz", "zbc"},
+		},
+		{
+			name:       "fewer candidates than n",
+			target:     "ubuntu",
+			candidates: []string{"ubuntu-jammy"},
+			n:          3,
+			want:       []string{"ubuntu-jammy"},
+		},
+		{
+			name:   "no candidates",
+			target: "ubuntu",
+			n:      3,
+			want:   []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := closestMatches(tt.target, tt.candidates, tt.n)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("closestMatches() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "abc", 0},
+		{"kitten", "sitting", 3},
+		{"xcode-15", "xcode-16", 1},
+		{"ä", "a", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := levenshtein(tt.a, tt.b); got != tt.want {
+				t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
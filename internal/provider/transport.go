@@ -0,0 +1,195 @@
+package provider
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"terraform-provider-bitrise/internal/bitrise"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	defaultMaxRetries   = 4
+	defaultRetryWaitMin = 1 * time.Second
+	defaultRetryWaitMax = 30 * time.Second
+)
+
+type authenticatedTransport struct {
+	token    string
+	endpoint string
+	base     http.RoundTripper
+	headers  map[string]string
+
+	maxRetries   int
+	retryWaitMin time.Duration
+	retryWaitMax time.Duration
+}
+
+func (t *authenticatedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.Header.Set("Authorization", t.token)
+	// Apply all headers from the headers map
+	for key, value := range t.headers {
+		req.Header.Set(key, value)
+	}
+
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 {
+			var err error
+			if attemptReq, err = rewindRequest(req); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if attempt >= t.maxRetries || !t.shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		wait, ok := t.backoff(attempt, resp)
+		if !ok {
+			tflog.Debug(ctx, "Not retrying Bitrise API request, the requested wait exceeds retry_wait_max", map[string]interface{}{
+				"method":         req.Method,
+				"path":           req.URL.Path,
+				"retry_wait_max": t.retryWaitMax.String(),
+			})
+			return resp, err
+		}
+
+		fields := map[string]interface{}{
+			"method":  req.Method,
+			"path":    req.URL.Path,
+			"attempt": attempt + 1,
+			"wait":    wait.String(),
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+		} else {
+			fields["status"] = resp.StatusCode
+			// Drain the body so the connection can be reused.
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		tflog.Debug(ctx, "Retrying Bitrise API request", fields)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// shouldRetry decides whether a failed attempt is worth repeating. Rate
+// limited requests were rejected before being processed and are always
+// retried; other transient failures only for requests that are idempotent.
+func (t *authenticatedTransport) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	if !isIdempotent(req) {
+		return false
+	}
+
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return bitrise.IsRetryable(req.Context())
+}
+
+// backoff returns how long to wait before the next attempt. Server hints in
+// Retry-After or X-RateLimit-Reset take precedence over the exponential
+// backoff, which is jittered to avoid retrying in lockstep. A hint longer
+// than retryWaitMax reports false: the request is not retried rather than
+// stalling the run, as plans and refreshes usually have no deadline.
+func (t *authenticatedTransport) backoff(attempt int, resp *http.Response) (time.Duration, bool) {
+	if resp != nil {
+		if wait, ok := serverWait(resp.Header, time.Now()); ok {
+			return wait, wait <= t.retryWaitMax
+		}
+	}
+
+	wait := float64(t.retryWaitMin) * math.Pow(2, float64(attempt))
+	if wait > float64(t.retryWaitMax) {
+		wait = float64(t.retryWaitMax)
+	}
+
+	half := wait / 2
+	return time.Duration(half + rand.Float64()*half), true
+}
+
+// serverWait reads the wait requested by the API, either through Retry-After
+// (seconds or HTTP date) or through the rate limit reset timestamp.
+func serverWait(header http.Header, now time.Time) (time.Duration, bool) {
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if date, err := http.ParseTime(value); err == nil {
+			return clampWait(date.Sub(now)), true
+		}
+	}
+
+	if header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return clampWait(time.Unix(reset, 0).Sub(now)), true
+		}
+	}
+
+	return 0, false
+}
+
+func clampWait(wait time.Duration) time.Duration {
+	if wait < 0 {
+		return 0
+	}
+	return wait
+}
+
+// rewindRequest clones req with a fresh copy of its body for another attempt.
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.GetBody == nil {
+		return clone, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("unable to rewind request body for retry: %w", err)
+	}
+	clone.Body = body
+
+	return clone, nil
+}
@@ -0,0 +1,344 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"terraform-provider-bitrise/internal/bitrise"
+)
+
+func TestServerWait(t *testing.T) {
+	now := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		header   map[string]string
+		wantWait time.Duration
+		wantOK   bool
+	}{
+		{
+			name: "no hint",
+		},
+		{
+			name:     "retry-after seconds",
+			header:   map[string]string{"Retry-After": "7"},
+			wantWait: 7 * time.Second,
+			wantOK:   true,
+		},
+		{
+			name:     "retry-after date",
+			header:   map[string]string{"Retry-After": now.Add(90 * time.Second).Format(http.TimeFormat)},
+			wantWait: 90 * time.Second,
+			wantOK:   true,
+		},
+		{
+			name:     "retry-after date in the past",
+			header:   map[string]string{"Retry-After": now.Add(-time.Minute).Format(http.TimeFormat)},
+			wantWait: 0,
+			wantOK:   true,
+		},
+		{
+			name:   "retry-after invalid",
+			header: map[string]string{"Retry-After": "soon"},
+		},
+		{
+			name: "rate limit reset",
+			header: map[string]string{
+				"X-RateLimit-Remaining": "0",
+				"X-RateLimit-Reset":     strconv.FormatInt(now.Add(20*time.Second).Unix(), 10),
+			},
+			wantWait: 20 * time.Second,
+			wantOK:   true,
+		},
+		{
+			name: "rate limit not exhausted",
+			header: map[string]string{
+				"X-RateLimit-Remaining": "3",
+				"X-RateLimit-Reset":     strconv.FormatInt(now.Add(20*time.Second).Unix(), 10),
+			},
+		},
+		{
+			name: "retry-after takes precedence",
+			header: map[string]string{
+				"Retry-After":           "2",
+				"X-RateLimit-Remaining": "0",
+				"X-RateLimit-Reset":     strconv.FormatInt(now.Add(20*time.Second).Unix(), 10),
+			},
+			wantWait: 2 * time.Second,
+			wantOK:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for key, value := range tt.header {
+				header.Set(key, value)
+			}
+
+			wait, ok := serverWait(header, now)
+			if wait != tt.wantWait || ok != tt.wantOK {
+				t.Errorf("serverWait() = %s, %t, want %s, %t", wait, ok, tt.wantWait, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	transport := &authenticatedTransport{
+		retryWaitMin: time.Second,
+		retryWaitMax: 30 * time.Second,
+	}
+
+	tests := []struct {
+		name       string
+		attempt    int
+		retryAfter string
+		wantMin    time.Duration
+		wantMax    time.Duration
+		wantOK     bool
+	}{
+		{
+			name:    "exponential",
+			attempt: 2,
+			wantMin: 2 * time.Second,
+			wantMax: 4 * time.Second,
+			wantOK:  true,
+		},
+		{
+			name:    "exponential capped",
+			attempt: 10,
+			wantMin: 15 * time.Second,
+			wantMax: 30 * time.Second,
+			wantOK:  true,
+		},
+		{
+			name:       "server hint within bound",
+			retryAfter: "30",
+			wantMin:    30 * time.Second,
+			wantMax:    30 * time.Second,
+			wantOK:     true,
+		},
+		{
+			name:       "server hint beyond bound",
+			retryAfter: "3600",
+			wantMin:    time.Hour,
+			wantMax:    time.Hour,
+			wantOK:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tt.retryAfter != "" {
+				resp.Header.Set("Retry-After", tt.retryAfter)
+			}
+
+			wait, ok := transport.backoff(tt.attempt, resp)
+			if ok != tt.wantOK || wait < tt.wantMin || wait > tt.wantMax {
+				t.Errorf("backoff() = %s, %t, want between %s and %s, %t", wait, ok, tt.wantMin, tt.wantMax, tt.wantOK)
+			}
+		})
+	}
+}
+
+// retryServer answers with the given statuses in order, repeating the last
+// one, and records the body of every request it receives.
+type retryServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	bodies   []string
+	statuses []int
+	header   http.Header
+}
+
+func newRetryServer(t *testing.T, header http.Header, statuses ...int) *retryServer {
+	t.Helper()
+
+	s := &retryServer{statuses: statuses, header: header}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		s.mu.Lock()
+		s.bodies = append(s.bodies, string(body))
+		status := s.statuses[min(len(s.bodies), len(s.statuses))-1]
+		s.mu.Unlock()
+
+		for key, values := range s.header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *retryServer) attempts() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.bodies...)
+}
+
+func newTestTransport(maxRetries int) *authenticatedTransport {
+	return &authenticatedTransport{
+		token:        "token",
+		base:         http.DefaultTransport,
+		maxRetries:   maxRetries,
+		retryWaitMin: time.Millisecond,
+		retryWaitMax: 10 * time.Millisecond,
+	}
+}
+
+func TestRoundTripRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		retryable    bool
+		statuses     []int
+		header       http.Header
+		maxRetries   int
+		wantAttempts int
+		wantStatus   int
+	}{
+		{
+			name:         "get retried on 503",
+			method:       http.MethodGet,
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusOK},
+			maxRetries:   4,
+			wantAttempts: 2,
+			wantStatus:   http.StatusOK,
+		},
+		{
+			name:         "get not retried on 500",
+			method:       http.MethodGet,
+			statuses:     []int{http.StatusInternalServerError},
+			maxRetries:   4,
+			wantAttempts: 1,
+			wantStatus:   http.StatusInternalServerError,
+		},
+		{
+			name:         "post not retried on 503",
+			method:       http.MethodPost,
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusOK},
+			maxRetries:   4,
+			wantAttempts: 1,
+			wantStatus:   http.StatusServiceUnavailable,
+		},
+		{
+			name:         "retryable post retried on 503",
+			method:       http.MethodPost,
+			retryable:    true,
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusOK},
+			maxRetries:   4,
+			wantAttempts: 2,
+			wantStatus:   http.StatusOK,
+		},
+		{
+			name:         "post retried on 429",
+			method:       http.MethodPost,
+			statuses:     []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusCreated},
+			maxRetries:   4,
+			wantAttempts: 3,
+			wantStatus:   http.StatusCreated,
+		},
+		{
+			name:         "max retries honoured",
+			method:       http.MethodGet,
+			statuses:     []int{http.StatusBadGateway},
+			maxRetries:   2,
+			wantAttempts: 3,
+			wantStatus:   http.StatusBadGateway,
+		},
+		{
+			name:         "retries disabled",
+			method:       http.MethodGet,
+			statuses:     []int{http.StatusTooManyRequests},
+			maxRetries:   0,
+			wantAttempts: 1,
+			wantStatus:   http.StatusTooManyRequests,
+		},
+		{
+			name:         "retry-after beyond retry_wait_max",
+			method:       http.MethodGet,
+			statuses:     []int{http.StatusTooManyRequests, http.StatusOK},
+			header:       http.Header{"Retry-After": []string{"3600"}},
+			maxRetries:   4,
+			wantAttempts: 1,
+			wantStatus:   http.StatusTooManyRequests,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newRetryServer(t, tt.header, tt.statuses...)
+
+			ctx := context.Background()
+			if tt.retryable {
+				ctx = bitrise.WithRetryable(ctx)
+			}
+			req, err := http.NewRequestWithContext(ctx, tt.method, server.URL, strings.NewReader("payload"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := newTestTransport(tt.maxRetries).RoundTrip(req)
+			if err != nil {
+				t.Fatalf("RoundTrip() error = %s", err)
+			}
+			resp.Body.Close()
+
+			attempts := server.attempts()
+			if len(attempts) != tt.wantAttempts || resp.StatusCode != tt.wantStatus {
+				t.Errorf("RoundTrip() made %d attempts with final status %d, want %d attempts with status %d",
+					len(attempts), resp.StatusCode, tt.wantAttempts, tt.wantStatus)
+			}
+			// Every attempt sends the full body again
+			for i, body := range attempts {
+				if body != "payload" {
+					t.Errorf("attempt %d sent body %q, want %q", i+1, body, "payload")
+				}
+			}
+		})
+	}
+}
+
+func TestRoundTripContextCancel(t *testing.T) {
+	server := newRetryServer(t, http.Header{"Retry-After": []string{"5"}}, http.StatusTooManyRequests)
+
+	transport := newTestTransport(4)
+	transport.retryWaitMax = time.Minute
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	resp, err := transport.RoundTrip(req)
+	if resp != nil {
+		resp.Body.Close()
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("RoundTrip() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("RoundTrip() returned after %s, want the backoff sleep to stop on cancellation", elapsed)
+	}
+	if attempts := len(server.attempts()); attempts != 1 {
+		t.Errorf("RoundTrip() made %d attempts, want 1", attempts)
+	}
+}
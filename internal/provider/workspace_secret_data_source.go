@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"terraform-provider-bitrise/internal/bitrise"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ datasource.DataSource = &WorkspaceSecretDataSource{}
+
+func NewWorkspaceSecretDataSource() datasource.DataSource {
+	return &WorkspaceSecretDataSource{}
+}
+
+type WorkspaceSecretDataSource struct {
+	client *bitrise.Client
+}
+
+type WorkspaceSecretDataSourceModel struct {
+	ID                       types.String `tfsdk:"id"`
+	WorkspaceSlug            types.String `tfsdk:"workspace_slug"`
+	Name                     types.String `tfsdk:"name"`
+	Value                    types.String `tfsdk:"value"`
+	IsProtected              types.Bool   `tfsdk:"is_protected"`
+	IsExposedForPullRequests types.Bool   `tfsdk:"is_exposed_for_pull_requests"`
+	ExpandInStepInputs       types.Bool   `tfsdk:"expand_in_step_inputs"`
+}
+
+func (d *WorkspaceSecretDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace_secret"
+}
+
+func (d *WorkspaceSecretDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reads a secret of a Bitrise workspace.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Data source identifier (workspace_slug/name)",
+				Computed:            true,
+			},
+			"workspace_slug": schema.StringAttribute{
+				MarkdownDescription: "The slug of the Bitrise workspace (organization)",
+				Required:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name (key) of the secret",
+				Required:            true,
+			},
+			"value": schema.StringAttribute{
+				MarkdownDescription: "The value of the secret, null for protected secrets",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"is_protected": schema.BoolAttribute{
+				MarkdownDescription: "Whether the secret value cannot be retrieved via the API",
+				Computed:            true,
+			},
+			"is_exposed_for_pull_requests": schema.BoolAttribute{
+				MarkdownDescription: "Whether the secret is available for pull request builds",
+				Computed:            true,
+			},
+			"expand_in_step_inputs": schema.BoolAttribute{
+				MarkdownDescription: "Whether variable expansion is enabled for the secret in step inputs",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *WorkspaceSecretDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*bitrise.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *bitrise.Client, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
+
+func (d *WorkspaceSecretDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx = d.client.LogContext(ctx)
+
+	var data WorkspaceSecretDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	workspaceSlug := data.WorkspaceSlug.ValueString()
+	name := data.Name.ValueString()
+
+	tflog.Debug(ctx, "Reading Bitrise workspace secret", map[string]interface{}{
+		"workspace_slug": workspaceSlug,
+		"name":           name,
+	})
+
+	secret, err := d.client.GetWorkspaceSecret(ctx, workspaceSlug, name)
+	if bitrise.IsNotFound(err) {
+		resp.Diagnostics.AddAttributeError(path.Root("name"), "Secret Not Found",
+			fmt.Sprintf("Workspace %s has no secret named %s.", workspaceSlug, name))
+		return
+	}
+	if err != nil {
+		tflog.Error(ctx, "Failed to read workspace secret", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to read workspace secret: %s", err))
+		return
+	}
+
+	// Protected values are never returned by the API
+	data.Value = types.StringNull()
+	if !secret.IsProtected {
+		data.Value = types.StringValue(secret.Value)
+	}
+
+	data.ID = types.StringValue(fmt.Sprintf("%s/%s", workspaceSlug, name))
+	data.IsProtected = types.BoolValue(secret.IsProtected)
+	data.IsExposedForPullRequests = types.BoolValue(secret.IsExposedForPullRequests)
+	data.ExpandInStepInputs = types.BoolValue(secret.ExpandInStepInputs)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,308 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"terraform-provider-bitrise/internal/bitrise"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ resource.Resource = &WorkspaceSecretResource{}
+var _ resource.ResourceWithImportState = &WorkspaceSecretResource{}
+var _ resource.ResourceWithModifyPlan = &WorkspaceSecretResource{}
+
+func NewWorkspaceSecretResource() resource.Resource {
+	return &WorkspaceSecretResource{}
+}
+
+// WorkspaceSecretResource manages a secret shared by every app of a
+// workspace (organization).
+type WorkspaceSecretResource struct {
+	client *bitrise.Client
+}
+
+type WorkspaceSecretResourceModel struct {
+	WorkspaceSlug            types.String `tfsdk:"workspace_slug"`
+	Name                     types.String `tfsdk:"name"`
+	Value                    types.String `tfsdk:"value"`
+	IsProtected              types.Bool   `tfsdk:"is_protected"`
+	IsExposedForPullRequests types.Bool   `tfsdk:"is_exposed_for_pull_requests"`
+	ExpandInStepInputs       types.Bool   `tfsdk:"expand_in_step_inputs"`
+	ID                       types.String `tfsdk:"id"`
+}
+
+func (r *WorkspaceSecretResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_workspace_secret"
+}
+
+func (r *WorkspaceSecretResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a secret of a Bitrise workspace. Workspace secrets are shared by every app of the workspace.",
+		Attributes: map[string]schema.Attribute{
+			"workspace_slug": schema.StringAttribute{
+				MarkdownDescription: "The slug of the Bitrise workspace (organization)",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name (key) of the secret. Must start with a letter or an underscore and contain only letters, digits and underscores.",
+				Required:            true,
+				Validators: []validator.String{
+					secretNameValidator,
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"value": schema.StringAttribute{
+				MarkdownDescription: "The value of the secret",
+				Required:            true,
+				Sensitive:           true,
+			},
+			"is_protected": schema.BoolAttribute{
+				MarkdownDescription: "If true, the secret value cannot be retrieved via the API. Bitrise does not allow removing the protection, so setting it back to false replaces the secret. Default: false",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"is_exposed_for_pull_requests": schema.BoolAttribute{
+				MarkdownDescription: "If true, the secret will be available for pull request builds. Default: false",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"expand_in_step_inputs": schema.BoolAttribute{
+				MarkdownDescription: "If true, variable expansion will be enabled for this secret in step inputs. Default: true",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The unique identifier of the secret (workspace_slug/name)",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *WorkspaceSecretResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*bitrise.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *bitrise.Client, got: %T", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
+
+// ModifyPlan replaces a secret whose protection is removed, as Bitrise
+// rejects unprotecting a secret.
+func (r *WorkspaceSecretResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on create and destroy
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan, state WorkspaceSecretResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if unprotectsSecret(state.IsProtected, plan.IsProtected) {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("is_protected"))
+	}
+}
+
+func (r *WorkspaceSecretResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx = r.client.LogContext(ctx)
+
+	var data WorkspaceSecretResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Creating Bitrise workspace secret", map[string]interface{}{
+		"workspace_slug": data.WorkspaceSlug.ValueString(),
+		"name":           data.Name.ValueString(),
+	})
+
+	secretReq := bitrise.CreateSecretParams{
+		Name:                     data.Name.ValueString(),
+		Value:                    data.Value.ValueString(),
+		IsProtected:              data.IsProtected.ValueBool(),
+		IsExposedForPullRequests: data.IsExposedForPullRequests.ValueBool(),
+		ExpandInStepInputs:       data.ExpandInStepInputs.ValueBool(),
+	}
+
+	if _, err := r.client.CreateWorkspaceSecret(ctx, data.WorkspaceSlug.ValueString(), secretReq); err != nil {
+		tflog.Error(ctx, "Failed to create workspace secret", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to create workspace secret: %s", err))
+		return
+	}
+
+	data.ID = types.StringValue(fmt.Sprintf("%s/%s", data.WorkspaceSlug.ValueString(), data.Name.ValueString()))
+
+	recordSecretWrite(ctx, workspaceSecretGetter(r.client, data.WorkspaceSlug.ValueString()), data.Name.ValueString(), resp.Private, resp.Private, &resp.Diagnostics)
+
+	tflog.Info(ctx, "Successfully created Bitrise workspace secret", map[string]interface{}{
+		"id": data.ID.ValueString(),
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *WorkspaceSecretResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx = r.client.LogContext(ctx)
+
+	var data WorkspaceSecretResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	workspaceSlug := data.WorkspaceSlug.ValueString()
+	name := data.Name.ValueString()
+
+	tflog.Debug(ctx, "Reading Bitrise workspace secret", map[string]interface{}{
+		"workspace_slug": workspaceSlug,
+		"name":           name,
+	})
+
+	secret, err := r.client.GetWorkspaceSecret(ctx, workspaceSlug, name)
+	if bitrise.IsNotFound(err) {
+		tflog.Info(ctx, "Workspace secret not found, removing from state", map[string]interface{}{
+			"workspace_slug": workspaceSlug,
+			"name":           name,
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		tflog.Error(ctx, "Failed to read workspace secret", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to read workspace secret: %s", err))
+		return
+	}
+
+	if refreshSecretWrites(ctx, secret, "workspace "+workspaceSlug, req.Private, resp.Private, &resp.Diagnostics) {
+		data.Value = types.StringNull()
+	}
+
+	data.IsProtected = types.BoolValue(secret.IsProtected)
+	data.IsExposedForPullRequests = types.BoolValue(secret.IsExposedForPullRequests)
+	data.ExpandInStepInputs = types.BoolValue(secret.ExpandInStepInputs)
+
+	// Protected values are never returned, the value from state is kept
+	if !secret.IsProtected && secret.Value != "" {
+		data.Value = types.StringValue(secret.Value)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *WorkspaceSecretResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx = r.client.LogContext(ctx)
+
+	var data WorkspaceSecretResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Updating Bitrise workspace secret", map[string]interface{}{
+		"workspace_slug": data.WorkspaceSlug.ValueString(),
+		"name":           data.Name.ValueString(),
+	})
+
+	isProtected := data.IsProtected.ValueBool()
+	isExposed := data.IsExposedForPullRequests.ValueBool()
+	expandInputs := data.ExpandInStepInputs.ValueBool()
+
+	secretReq := bitrise.UpdateSecretParams{
+		Value:                    data.Value.ValueString(),
+		IsProtected:              &isProtected,
+		IsExposedForPullRequests: &isExposed,
+		ExpandInStepInputs:       &expandInputs,
+	}
+
+	if err := r.client.UpdateWorkspaceSecret(ctx, data.WorkspaceSlug.ValueString(), data.Name.ValueString(), secretReq); err != nil {
+		tflog.Error(ctx, "Failed to update workspace secret", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to update workspace secret: %s", err))
+		return
+	}
+
+	recordSecretWrite(ctx, workspaceSecretGetter(r.client, data.WorkspaceSlug.ValueString()), data.Name.ValueString(), req.Private, resp.Private, &resp.Diagnostics)
+
+	tflog.Info(ctx, "Successfully updated Bitrise workspace secret")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *WorkspaceSecretResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx = r.client.LogContext(ctx)
+
+	var data WorkspaceSecretResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Deleting Bitrise workspace secret", map[string]interface{}{
+		"workspace_slug": data.WorkspaceSlug.ValueString(),
+		"name":           data.Name.ValueString(),
+	})
+
+	err := r.client.DeleteWorkspaceSecret(ctx, data.WorkspaceSlug.ValueString(), data.Name.ValueString())
+	// 404 means already deleted, which is fine
+	if bitrise.IsNotFound(err) {
+		tflog.Info(ctx, "Workspace secret already deleted")
+		return
+	}
+	if err != nil {
+		tflog.Error(ctx, "Failed to delete workspace secret", map[string]interface{}{"error": err.Error()})
+		resp.Diagnostics.AddError("API Error", fmt.Sprintf("Failed to delete workspace secret: %s", err))
+		return
+	}
+
+	tflog.Info(ctx, "Successfully deleted Bitrise workspace secret")
+}
+
+func (r *WorkspaceSecretResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import ID should be in the format: workspace_slug/secret_name
+	parts := strings.Split(req.ID, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("Import ID must be in the format 'workspace_slug/secret_name', got: %s", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("workspace_slug"), parts[0])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), parts[1])...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"gopkg.in/yaml.v3"
+)
+
+var _ basetypes.StringTypable = yamlStringType{}
+var _ basetypes.StringValuableWithSemanticEquals = yamlString{}
+var _ xattr.ValidateableAttribute = yamlString{}
+
+// yamlStringType is a string type holding a YAML document. Its values compare
+// by content, so the API reordering keys, changing quoting or dropping
+// comments does not show up as a change in plan.
+type yamlStringType struct {
+	basetypes.StringType
+}
+
+func (t yamlStringType) String() string {
+	return "yamlStringType"
+}
+
+func (t yamlStringType) Equal(o attr.Type) bool {
+	other, ok := o.(yamlStringType)
+	if !ok {
+		return false
+	}
+
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t yamlStringType) ValueType(ctx context.Context) attr.Value {
+	return yamlString{}
+}
+
+func (t yamlStringType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return yamlString{StringValue: in}, nil
+}
+
+func (t yamlStringType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+
+	return yamlString{StringValue: stringValue}, nil
+}
+
+// yamlString is the value of a yamlStringType.
+type yamlString struct {
+	basetypes.StringValue
+}
+
+func newYAMLStringValue(value string) yamlString {
+	return yamlString{StringValue: basetypes.NewStringValue(value)}
+}
+
+func (v yamlString) Type(ctx context.Context) attr.Type {
+	return yamlStringType{}
+}
+
+func (v yamlString) Equal(o attr.Value) bool {
+	other, ok := o.(yamlString)
+	if !ok {
+		return false
+	}
+
+	return v.StringValue.Equal(other.StringValue)
+}
+
+// StringSemanticEquals reports whether both documents decode to the same data.
+// Aliases are resolved while decoding, so an inlined anchor is equal too.
+func (v yamlString) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(yamlString)
+	if !ok {
+		diags.AddError(
+			"Semantic Equality Check Error",
+			fmt.Sprintf("Expected value type %T but got value type %T.", v, newValuable),
+		)
+		return false, diags
+	}
+
+	// Invalid documents are reported by ValidateAttribute, here they are
+	// simply not equal
+	var current, updated interface{}
+	if err := yaml.Unmarshal([]byte(v.ValueString()), &current); err != nil {
+		return false, diags
+	}
+	if err := yaml.Unmarshal([]byte(newValue.ValueString()), &updated); err != nil {
+		return false, diags
+	}
+
+	return reflect.DeepEqual(current, updated), diags
+}
+
+func (v yamlString) ValidateAttribute(ctx context.Context, req xattr.ValidateAttributeRequest, resp *xattr.ValidateAttributeResponse) {
+	if v.IsNull() || v.IsUnknown() {
+		return
+	}
+
+	var doc interface{}
+	if err := yaml.Unmarshal([]byte(v.ValueString()), &doc); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid YAML",
+			fmt.Sprintf("The value is not a valid YAML document: %s", err),
+		)
+	}
+}
@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+func TestYAMLStringSemanticEquals(t *testing.T) {
+	tests := []struct {
+		name      string
+		current   string
+		updated   string
+		wantEqual bool
+	}{
+		{
+			name:      "identical",
+			current:   "format_version: \"13\"\n",
+			updated:   "format_version: \"13\"\n",
+			wantEqual: true,
+		},
+		{
+			name:      "reordered keys, quoting and comments",
+			current:   "format_version: \"13\"\nproject_type: ios\n",
+			updated:   "# managed by terraform\nproject_type: 'ios'\nformat_version: \"13\"\n",
+			wantEqual: true,
+		},
+		{
+			name:      "flow and block style",
+			current:   "envs: [a, b]\n",
+			updated:   "envs:\n  - a\n  - b\n",
+			wantEqual: true,
+		},
+		{
+			name:      "alias inlined",
+			current:   "base: &base\n  stack: linux\nwf:\n  <<: *base\n",
+			updated:   "base:\n  stack: linux\nwf:\n  stack: linux\n",
+			wantEqual: true,
+		},
+		{
+			name:    "changed value",
+			current: "format_version: \"13\"\n",
+			updated: "format_version: \"11\"\n",
+		},
+		{
+			name:    "string and number",
+			current: "format_version: \"13\"\n",
+			updated: "format_version: 13\n",
+		},
+		{
+			name:      "null and empty document",
+			current:   "null",
+			updated:   "",
+			wantEqual: true,
+		},
+		{
+			name:      "tilde and comment only document",
+			current:   "~\n",
+			updated:   "# nothing here\n",
+			wantEqual: true,
+		},
+		{
+			name:    "null and empty mapping",
+			current: "null",
+			updated: "{}",
+		},
+		{
+			name:    "empty document and data",
+			current: "",
+			updated: "format_version: \"13\"\n",
+		},
+		{
+			name:    "invalid current",
+			current: "format_version: [\"13\"\n",
+			updated: "format_version: \"13\"\n",
+		},
+		{
+			name:    "invalid updated",
+			current: "format_version: \"13\"\n",
+			updated: "format_version: [\"13\"\n",
+		},
+		{
+			name:    "invalid on both sides",
+			current: "format_version: [\"13\"\n",
+			updated: "format_version: [\"13\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			equal, diags := newYAMLStringValue(tt.current).StringSemanticEquals(context.Background(), newYAMLStringValue(tt.updated))
+			if diags.HasError() {
+				t.Fatalf("StringSemanticEquals() diagnostics = %v", diags)
+			}
+			if equal != tt.wantEqual {
+				t.Errorf("StringSemanticEquals(%q, %q) = %t, want %t", tt.current, tt.updated, equal, tt.wantEqual)
+			}
+		})
+	}
+}
+
+func TestYAMLStringSemanticEqualsWrongType(t *testing.T) {
+	_, diags := newYAMLStringValue("a: 1").StringSemanticEquals(context.Background(), basetypes.NewStringValue("a: 1"))
+	if !diags.HasError() {
+		t.Error("StringSemanticEquals() with a plain string value did not return an error")
+	}
+}
+
+func TestYAMLStringValidateAttribute(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     yamlString
+		wantError bool
+	}{
+		{"valid", newYAMLStringValue("format_version: \"13\"\n"), false},
+		{"empty", newYAMLStringValue(""), false},
+		{"null", yamlString{StringValue: basetypes.NewStringNull()}, false},
+		{"unknown", yamlString{StringValue: basetypes.NewStringUnknown()}, false},
+		{"invalid", newYAMLStringValue("format_version: [\"13\"\n"), true},
+		{"tab indentation", newYAMLStringValue("workflows:\n\tprimary: {}\n"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := xattr.ValidateAttributeRequest{Path: path.Root("bitrise_yml")}
+			resp := &xattr.ValidateAttributeResponse{}
+			tt.value.ValidateAttribute(context.Background(), req, resp)
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Errorf("ValidateAttribute() diagnostics = %v, want error %t", resp.Diagnostics, tt.wantError)
+			}
+		})
+	}
+}